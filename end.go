@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"theStartupTM/sim"
+)
+
+type endKeyMap struct {
+	Restart key.Binding
+	Quit    key.Binding
+}
+
+func (k endKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Restart, k.Quit}
+}
+
+func (k endKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var endKeys = endKeyMap{
+	Restart: key.NewBinding(
+		key.WithKeys("enter", "r"),
+		key.WithHelp("enter", "try again"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc", "quit"),
+	),
+}
+
+func (m model) updateEnd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, endKeys.Restart):
+		return m.restart(), nil
+	case key.Matches(msg, endKeys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+var endingArt = map[sim.Ending]string{
+	sim.Crushed: `
+     $ $$ $ $$ $
+   $$ $$$$$$ $$ $$
+  $$$$ $$$$$$ $$$$$
+ ┌─┬┴\/\/──\/\/┴┬─┐
+ │◫ ◫ ◫ \/ ◫ ◫ ◫ ◫│
+ └──────┮◚◚┭──────┘`,
+	sim.Collapsed: `
+ │\
+ │ \      /\
+ │  \    /  \
+ │   \  /    \
+ │    \/      \___
+ └────────────────`,
+	sim.Bankrupt: `
+    .──────────.
+    │  CLOSED  │
+    '──────────'
+ ┌─┬┴──────────┴┬─┐
+ │ │  FOR  SALE │ │
+ └──────┮  ┭──────┘`,
+	sim.Ousted: `
+      ┌──────┐
+      │ ▢ ▢  │
+    ┌─┴──────┴─┐
+    │ ░░░░░░░░ │
+    └──────────┘
+  ~ your stuff ~`,
+}
+
+var endTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1"))
+var endArtStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("35")).MarginRight(4)
+var endStatsStyle = lipgloss.NewStyle().MarginRight(4)
+var endLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Width(20)
+
+// EndStatsView lists how the run went.
+func (m model) EndStatsView() string {
+	st := m.Stats
+	elapsed := time.Duration(st.Elapsed * float64(time.Second)).Round(time.Second)
+	rows := [][2]string{
+		{"Survived", elapsed.String()},
+		{"Difficulty", m.difficulty.String()},
+		{"Seed", fmt.Sprintf("%d", m.Seed)},
+		{"Peak cash", fmt.Sprintf("$%d", st.PeakCash)},
+		{"Peak company value", fmt.Sprintf("%d", st.PeakPricePerShare)},
+		{"Most users", fmt.Sprintf("%d", st.PeakUsers)},
+		{"Features shipped", fmt.Sprintf("%d", st.FeaturesShipped)},
+		{"Bugs shipped", fmt.Sprintf("%d", st.BugsShipped)},
+		{"Bugs fixed", fmt.Sprintf("%d", st.BugsFixed)},
+		{"Raised", fmt.Sprintf("$%d, %d targets missed", st.Raised, st.TargetsMissed)},
+		{"Events weathered", fmt.Sprintf("%d", st.Events)},
+		{"Spent on upgrades", fmt.Sprintf("$%d", st.SpentOnUpgrades)},
+		{"Founder stake", fmt.Sprintf("%.0f%%", m.Ownership*100)},
+		{"Most staff", fmt.Sprintf("%d devs, %d QA, %d marketers", st.PeakDevs, st.PeakQA, st.PeakMarketers)},
+		{"", fmt.Sprintf("%d strategists, %d influencers", st.PeakStrategists, st.PeakInfluencers)},
+	}
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = endLabelStyle.Render(row[0]) + row[1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m model) EndView() string {
+	art := endArtStyle.Render(strings.TrimPrefix(endingArt[m.Ending], "\n"))
+	stats := endStatsStyle.Render(m.EndStatsView())
+	footer := m.helpModel.ShortHelpView(endKeys.ShortHelp())
+	if m.enteringInitials {
+		footer = scoreHighlightStyle.Render("New high score!") + "  " + m.initials.View() + "\n" +
+			m.helpModel.ShortHelpView([]key.Binding{initialsKeys.Submit, initialsKeys.Skip})
+	}
+	card := lipgloss.JoinVertical(lipgloss.Center,
+		endTitleStyle.Render("GAME OVER"),
+		"",
+		m.FailureCause,
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Center, art, stats, ScoresView(m.scores, 5, m.scoreRank)),
+		"",
+		footer,
+	)
+	return baseScreenStyle(m).Align(lipgloss.Center, lipgloss.Center).Render(card)
+}
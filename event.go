@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"theStartupTM/sim"
+)
+
+type eventKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Select key.Binding
+}
+
+func (k eventKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Select}
+}
+
+func (k eventKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var eventKeys = eventKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Select: key.NewBinding(
+		key.WithKeys("enter", " "),
+		key.WithHelp("enter", "decide"),
+	),
+}
+
+// showEvent stops the clock until the player decides what to do about the
+// event that just came up.
+func (m model) showEvent() model {
+	m.scene = Choosing
+	m.gameTicking = false
+	m.eventCursor = 0
+	return m
+}
+
+func (m model) updateEvent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e, ok := sim.PendingEvent(m.balance, m.State)
+	if !ok || len(e.Choices) == 0 {
+		// The deck changed under a saved run; there's nothing to decide.
+		m.PendingEvent = ""
+		return m.resume(), nil
+	}
+	switch {
+	case key.Matches(msg, eventKeys.Up):
+		m.eventCursor = (m.eventCursor + len(e.Choices) - 1) % len(e.Choices)
+
+	case key.Matches(msg, eventKeys.Down):
+		m.eventCursor = (m.eventCursor + 1) % len(e.Choices)
+
+	case key.Matches(msg, eventKeys.Select):
+		s, err := sim.Choose(m.balance, m.State, m.eventCursor)
+		if err != nil {
+			m.notice = "Can't do that: " + err.Error()
+			m.noticeFrames = NOTICE_FRAMES
+			return m, nil
+		}
+		m.State = s
+		return m.resume(), nil
+	}
+	return m, nil
+}
+
+var eventTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1"))
+var unaffordableStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+func (m model) EventDialogView() string {
+	e, _ := sim.PendingEvent(m.balance, m.State)
+	items := make([]string, len(e.Choices))
+	for i, c := range e.Choices {
+		style := lipgloss.NewStyle()
+		if !c.Affordable(m.State) {
+			style = unaffordableStyle
+		} else if i == m.eventCursor {
+			style = pauseSelectedStyle
+		}
+		cursor := "  "
+		if i == m.eventCursor {
+			cursor = "> "
+		}
+		items[i] = style.Render(fmt.Sprintf("%s%d. %s", cursor, i+1, c.Label))
+	}
+	dialog := eventTitleStyle.Render(strings.ToUpper(e.Name)) + "\n\n" +
+		e.Text + "\n\n" +
+		strings.Join(items, "\n") + "\n\n" +
+		m.helpModel.ShortHelpView(eventKeys.ShortHelp())
+	return pauseBorder.Render(dialog)
+}
+
+// ChoosingView draws the event dialog on top of the frozen game.
+func (m model) ChoosingView() string {
+	base := m.GameView()
+	dialog := m.EventDialogView()
+	lines := strings.Split(dialog, "\n")
+	width := maxWidth(lines)
+	return PlaceOverlay(m.width/2-width/2, m.height/2-len(lines)/2, dialog, base, true)
+}
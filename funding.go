@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"theStartupTM/sim"
+)
+
+// raise signs the term sheet investors are offering, if there is one.
+func (m model) raise() model {
+	r, _ := sim.TermSheet(m.balance, m.State)
+	s, err := sim.Raise(m.balance, m.State)
+	if err != nil {
+		m.notice = "Can't raise: " + err.Error()
+		m.noticeFrames = NOTICE_FRAMES
+		return m
+	}
+	m.State = s
+	m.notice = fmt.Sprintf("Closed the %s round: +$%d for %.0f%% of the company", r.Name, r.Raise, r.Equity*100)
+	m.noticeFrames = NOTICE_FRAMES
+	return m
+}
+
+// fundingBanner announces term sheets as they arrive and board meetings that
+// go badly. before is the state from the start of the tick.
+func (m model) fundingBanner(before sim.State) model {
+	r, _ := sim.TermSheet(m.balance, m.State)
+	switch {
+	case m.BoardPressure:
+		m.banner = fmt.Sprintf("✖ BOARD MEETING! Missed the %d target. Your stake is down to %.0f%% ✖", m.Target, m.Ownership*100)
+		m.bannerStyle = backlashStyle
+		m.bannerFrames = BANNER_FRAMES
+	case m.RoundsOffered > before.RoundsOffered:
+		m.banner = fmt.Sprintf("✉ TERM SHEET! %s: $%d for %.0f%%. Press %s to sign ✉",
+			r.Name, r.Raise, r.Equity*100, devKeys.Raise.Help().Key)
+		m.bannerStyle = viralStyle
+		m.bannerFrames = BANNER_FRAMES
+	}
+	return m
+}
+
+// fundingRow is the Founder Stake line of the table: the founder's share,
+// the next round and how the board's target is going.
+func (m model) fundingRow() []string {
+	row := []string{"Founder Stake", fmt.Sprintf("%.0f%%", m.Ownership*100), "", "", ""}
+	if r, offered := sim.TermSheet(m.balance, m.State); r.Name != "" {
+		switch {
+		case offered:
+			row[2] = fmt.Sprintf("%s OFFER (%s)", r.Name, devKeys.Raise.Help().Key)
+		case m.Target > 0:
+			row[2] = fmt.Sprintf("%s after goal", r.Name)
+		default:
+			row[2] = fmt.Sprintf("%s at %d", r.Name, r.Price)
+		}
+		row[3] = fmt.Sprintf("$%d for %.0f%%", r.Raise, r.Equity*100)
+	}
+	if m.Target > 0 {
+		row[4] = fmt.Sprintf("goal %d %.0fs", m.Target, m.TargetDeadline-m.Stats.Elapsed)
+	}
+	return row
+}
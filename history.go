@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HISTORY_LEN is how many game ticks of history the charts remember.
+const HISTORY_LEN = 300
+
+// SPARK_WIDTH is how many columns each chart gets.
+var SPARK_WIDTH = 40
+
+type snapshot struct {
+	cash          int
+	users         int
+	bugs          int
+	pricePerShare int
+}
+
+// history is a ring buffer of one snapshot per game tick. It's an array, not
+// a slice, so copies of the model don't share it.
+type history struct {
+	samples [HISTORY_LEN]snapshot
+	next    int
+	len     int
+}
+
+func (h history) push(s snapshot) history {
+	h.samples[h.next] = s
+	h.next = (h.next + 1) % HISTORY_LEN
+	h.len = min(h.len+1, HISTORY_LEN)
+	return h
+}
+
+// series pulls one value out of every snapshot, oldest first.
+func (h history) series(value func(snapshot) int) []float64 {
+	out := make([]float64, h.len)
+	start := (h.next - h.len + HISTORY_LEN) % HISTORY_LEN
+	for i := range out {
+		out[i] = float64(value(h.samples[(start+i)%HISTORY_LEN]))
+	}
+	return out
+}
+
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline squeezes values into width columns, averaging neighbours when
+// there are more values than columns, and scales them between their own
+// lowest and highest point.
+func sparkline(values []float64, width int) string {
+	if len(values) == 0 {
+		return strings.Repeat(" ", width)
+	}
+	columns := min(width, len(values))
+	buckets := make([]float64, columns)
+	for c := range buckets {
+		from := c * len(values) / columns
+		to := (c + 1) * len(values) / columns
+		sum := 0.
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		buckets[c] = sum / float64(to-from)
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range buckets {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range buckets {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(sparkRunes)-1))
+		}
+		b.WriteRune(sparkRunes[level])
+	}
+	b.WriteString(strings.Repeat(" ", width-columns))
+	return b.String()
+}
+
+var chartBorder = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("63")).MarginLeft(1)
+var chartLabelStyle = lipgloss.NewStyle().Width(8)
+
+// ChartView plots the recent history of the numbers that matter most.
+func (m model) ChartView() string {
+	charts := []struct {
+		label string
+		value func(snapshot) int
+		style lipgloss.Style
+	}{
+		{"Cash", func(s snapshot) int { return s.cash }, cashStyle},
+		{"Users", func(s snapshot) int { return s.users }, startupStyle},
+		{"Bugs", func(s snapshot) int { return s.bugs }, lossStyle},
+		{"Value", func(s snapshot) int { return s.pricePerShare }, speedStyle},
+	}
+
+	span := time.Duration(HISTORY_LEN) * SIM_STEP
+	lines := []string{fmt.Sprintf("last %v", span)}
+	for _, c := range charts {
+		values := m.history.series(c.value)
+		current := 0
+		if len(values) > 0 {
+			current = int(values[len(values)-1])
+		}
+		lines = append(lines, chartLabelStyle.Render(c.label)+
+			c.style.Render(sparkline(values, SPARK_WIDTH))+
+			fmt.Sprintf(" %9d", current))
+	}
+	return chartBorder.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHistory(t *testing.T) {
+	tests := []struct {
+		name   string
+		pushes int
+		want   []float64
+	}{
+		{"empty", 0, []float64{}},
+		{"a few ticks", 3, []float64{1, 2, 3}},
+		{"exactly full", HISTORY_LEN, count(1, HISTORY_LEN)},
+		{"wrapped around", HISTORY_LEN + 5, count(6, HISTORY_LEN+5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h history
+			for i := 1; i <= tt.pushes; i++ {
+				h = h.push(snapshot{cash: i})
+			}
+			got := h.series(func(s snapshot) int { return s.cash })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("series = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryCopiesDontShare(t *testing.T) {
+	var h history
+	h = h.push(snapshot{cash: 1})
+	copied := h
+	h = h.push(snapshot{cash: 2})
+	if got := copied.series(func(s snapshot) int { return s.cash }); len(got) != 1 {
+		t.Errorf("copy grew to %v when the original was pushed to", got)
+	}
+}
+
+// count returns from, from+1, ... to.
+func count(from, to int) []float64 {
+	var out []float64
+	for i := from; i <= to; i++ {
+		out = append(out, float64(i))
+	}
+	return out
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"no values", nil, 4, "    "},
+		{"flat", []float64{5, 5, 5}, 3, "▁▁▁"},
+		{"rising", count(1, 8), 8, "▁▂▃▄▅▆▇█"},
+		{"falling", []float64{3, 2, 1}, 3, "█▄▁"},
+		{"padded to width", []float64{0, 1}, 4, "▁█  "},
+		{"averaged into columns", []float64{0, 0, 10, 10}, 2, "▁█"},
+		{"negative values", []float64{-10, 0, -10}, 3, "▁█▁"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sparkline(tt.values, tt.width)
+			if got != tt.want {
+				t.Errorf("sparkline = %q, want %q", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n != tt.width {
+				t.Errorf("sparkline is %d columns, want %d", n, tt.width)
+			}
+		})
+	}
+
+	// Many more values than columns still fill the width exactly.
+	if got := sparkline(count(1, HISTORY_LEN), SPARK_WIDTH); !strings.HasPrefix(got, "▁") || !strings.HasSuffix(got, "█") {
+		t.Errorf("sparkline of %d rising values = %q, want ▁ up to █", HISTORY_LEN, got)
+	}
+}
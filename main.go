@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math"
 	"math/rand"
@@ -16,31 +17,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/reflow/ansi"
+	"theStartupTM/sim"
 )
 
 type model struct {
-    pricePerShare int
-    cash int
-    cashPerSecond int
-    users int
-    usersPerSecondFromFeatures float64
-    usersPerSecondFromMarketers float64
-    usersPerSecondFromBugs float64
-    features int
-    featuresPerSecond float64
-    bugs int
-    bugsPerSecondPerFeature float64
-    bugsPerSecondPerDev float64
-    devs int
-    qa int
-    marketers int
-    
-    progressTowardFeature float64 
-    progressTowardBug float64 
-    progressTowardBugFix float64
-    progressTowardUser float64 
-    progressTowardLostUser float64
-    copyPasteModifier int
+    sim.State
+    baseBalance sim.Balance
+    balance sim.Balance
+    difficulty sim.Difficulty
 
     height int
     width int
@@ -51,17 +35,62 @@ type model struct {
     helpWindow bool
     helpModel help.Model
 
+    chartWindow bool
+    history history
+
+    shopOpen bool
+    shopCursor int
+    eventCursor int
+
+    // The typing minigame behind the feature keys, and the typo hunt behind
+    // the bug keys.
+    coding bool
+    snippet string
+    code textinput.Model
+    hunting bool
+    huntLines []string
+    huntTypo int
+
     
     cashParticles [20]particle
     cashParticlesVisible int
 
-    devFocus int
     devFocusProgress progress.Model
     
     gameTicking bool
+    // lastTick is when the game loop last woke; sinceStep is real time
+    // not yet simulated.
+    lastTick time.Time
+    sinceStep time.Duration
+    speed int // index into SPEEDS
 
     scene GameScene
-    failureCause string
+    pauseCursor pauseItem
+    ticks int
+    hasSave bool
+
+    notice string
+    noticeFrames int
+    scores []score
+    scoreRank int // where this run landed on the leaderboard, -1 if it didn't
+    enteringInitials bool
+    initials textinput.Model
+
+    banner string
+    bannerStyle lipgloss.Style
+    bannerFrames int
+
+    // recorder writes key presses to a replay file; nil when not recording.
+    recorder *recorder
+    recordedEnd bool
+    // replaying is set when keys come from replay rather than the keyboard.
+    replaying bool
+    replay []replayEvent
+
+    rng *rand.Rand
+    // seedFixed keeps the seed from --seed across restarts instead of
+    // rolling a new one.
+    seedFixed bool
 
     // debug
     debug string
@@ -71,7 +100,10 @@ type GameScene int
 const (
     Start GameScene = iota
     Game
+    Paused
     End
+    // Choosing is the game frozen on an event the player has to respond to.
+    Choosing
 )
 
 type particle struct {
@@ -79,41 +111,22 @@ type particle struct {
     y int
 }
 
+// NOTICE_FRAMES is how many frame ticks a notice stays on screen.
+var NOTICE_FRAMES = 36
+
+// BANNER_FRAMES is how many frame ticks a viral banner stays on screen.
+var BANNER_FRAMES = 36
+
 var baseStyle = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("240"))
-var USERS_PER_SECOND_PER_FEATURE = 1./4.
-var USERS_PER_SECOND_PER_MARKERTER = 1./4.
-var USERS_PER_SECOND_PER_BUG= 1./8.
-var CASH_PER_SECOND_PER_USER_PER_FEATURE = 1
-var BUGS_PER_SECOND_PER_FEATURE = 1./100.
-var BUGS_PER_SECOND_PER_DEV = 1./40.
-var BUGS_PER_SECOND_PER_QA = 1./60.
-var FEATURES_PER_SECOND_PER_DEV = 1./2.
-var DEV_SALARY_PER_SECOND = 1
-var PRICE_PER_FEATURE = 100
-var PRICE_PER_USER = 100
-var PRICE_PER_BUG = -500
-var PRICE_PER_DEV = 1000
-var PRICE_PER_MARKETER = 1000
-var CASH_CAP = 2000000
-
-
-func initialModel() model {
+
+
+func initialModel(b sim.Balance, seed int64) model {
         return model {
-        pricePerShare: 0,
-        cash: 0,
-        cashPerSecond: 0,
-        users: 1,
-        features: 0,
-        bugs:0,
-        devs:0,
-        marketers:0,
-
-        progressTowardFeature:0,
-        progressTowardBug:0,
-        progressTowardBugFix:0,
-        progressTowardUser:0,
-        progressTowardLostUser: 0,
-        copyPasteModifier: 0,
+        State: sim.New(b, seed),
+        rng: rand.New(rand.NewSource(seed)),
+        baseBalance: b,
+        balance: b,
+        difficulty: sim.Normal,
 
         height: 0,
         width: 0,
@@ -124,11 +137,14 @@ func initialModel() model {
         cashParticles: [20]particle{},
         cashParticlesVisible: 0,
 
-        devFocus: 10,
-        devFocusProgress: progress.New(progress.WithSolidFill("4"), progress.WithWidth(10)),
+        devFocusProgress: progress.New(progress.WithSolidFill("4"), progress.WithWidth(10), progress.WithoutPercentage()),
 
         scene: Start,
         gameTicking: true,
+        speed: DEFAULT_SPEED,
+        hasSave: saveExists(),
+        scores: loadScores(),
+        scoreRank: -1,
     }
 }
 
@@ -137,76 +153,135 @@ func (m model) Init() tea.Cmd {
     return tea.Batch(doGameTick(), doFrameTick(), textinput.Blink)
 }
 
+// SIM_STEP is how much game time one onGameTick simulates. The game loop
+// runs as many of them as real time calls for, however late the tea.Tick
+// that woke it up.
+var SIM_STEP = time.Second
+
+// POLLS_PER_STEP is how many times per SIM_STEP the game loop checks the
+// clock, so steps land close to when they're due.
+var POLLS_PER_STEP = 4
+
+// MAX_CATCH_UP_STEPS caps how many steps one wake-up may run at 1x. Anything
+// beyond it (a suspended process, a laptop lid) is dropped, not fast-forwarded.
+var MAX_CATCH_UP_STEPS = 5
+
+// SPEEDS are the game speed multipliers the player can pick from; 0 stops the
+// clock while leaving the screen live.
+var SPEEDS = []int{0, 1, 2, 5, 10}
+var DEFAULT_SPEED = 1
+
 type GameTickMsg time.Time
 
 func doGameTick() tea.Cmd {
-    tick := tea.Tick(time.Second, func(t time.Time) tea.Msg {
+    tick := tea.Tick(SIM_STEP / time.Duration(POLLS_PER_STEP), func(t time.Time) tea.Msg {
         return GameTickMsg(t)
     })
     return tick
 }
 
+func (m model) running() bool {
+    return m.gameTicking && m.scene == Game
+}
+
+// advance runs however many fixed steps have come due by now.
+func (m model) advance(now time.Time) (model, tea.Cmd) {
+    var cmds []tea.Cmd
+    if (m.replaying) {
+        var cmd tea.Cmd
+        m, cmd = m.feedReplay()
+        cmds = append(cmds, cmd)
+    }
+
+    // Time spent paused or on a menu doesn't count.
+    if (!m.running() || m.lastTick.IsZero()) {
+        m.lastTick = now
+        return m, tea.Batch(cmds...)
+    }
+    speed := SPEEDS[m.speed]
+    m.sinceStep += now.Sub(m.lastTick) * time.Duration(speed)
+    m.lastTick = now
+
+    autosave := false
+    for steps := 0; m.sinceStep >= SIM_STEP && m.running(); steps++ {
+        if (steps == MAX_CATCH_UP_STEPS * speed) {
+            m.sinceStep = 0
+            break
+        }
+        if (m.replaying) {
+            var cmd tea.Cmd
+            m, cmd = m.feedReplay()
+            cmds = append(cmds, cmd)
+        }
+        m = onGameTick(m)
+        m.sinceStep -= SIM_STEP
+        autosave = autosave || m.ticks % AUTOSAVE_TICKS == 0
+    }
+
+    if (m.scene == End && m.recorder != nil && !m.recordedEnd) {
+        m = m.recordErr(m.recorder.end(m.ticks))
+        m.recordedEnd = true
+    }
+    // A finished run can't be picked up again. Once the game is over advance
+    // stops stepping, so this only happens on the tick the run ended.
+    if (m.scene == End) {
+        m.hasSave = false
+        cmds = append(cmds, m.discardSave())
+    }
+    if (autosave && m.scene == Game) {
+        cmds = append(cmds, m.save())
+    }
+    return m, tea.Batch(cmds...)
+}
+
+// onGameTick runs one SIM_STEP of the game.
 func onGameTick(m model) model { 
-    if (!m.gameTicking){
+    if (!m.running()){
         return m
     }
 
-    m.featuresPerSecond = float64(m.devs) * FEATURES_PER_SECOND_PER_DEV
-    m.progressTowardFeature += m.featuresPerSecond
-    newFeatures := math.Floor(m.progressTowardFeature)
-    m.features += int(newFeatures)
-    m.progressTowardFeature -= newFeatures
-
-    bugsFixedPerSecond := float64(m.qa) * BUGS_PER_SECOND_PER_QA
-    m.progressTowardBugFix += bugsFixedPerSecond
-    bugFixes := math.Floor(m.progressTowardBugFix)
-    m.bugs -= int(bugFixes)
-    m.progressTowardBugFix -= bugFixes 
-    
+    before := m.State
+    m.State = sim.Step(m.balance, m.State, SIM_STEP.Seconds())
+    m.ticks += 1
+    m.history = m.history.push(snapshot{
+        cash: m.Cash,
+        users: m.Users,
+        bugs: m.Bugs,
+        pricePerShare: m.PricePerShare,
+    })
+    m.cashParticlesVisible = 0
+    if (m.CashPerSecond != 0) {
+        flow := math.Abs(float64(m.CashPerSecond))
+        m.cashParticlesVisible = min(int(math.Log2(flow)), len(m.cashParticles))
+    }
 
-    m.bugsPerSecondPerDev = float64(m.devs) * BUGS_PER_SECOND_PER_DEV
-    m.bugsPerSecondPerFeature = float64(m.features) * BUGS_PER_SECOND_PER_FEATURE
-    bugsPerSecond := m.bugsPerSecondPerDev + m.bugsPerSecondPerFeature
-    m.progressTowardBug += bugsPerSecond
-    newBugs := math.Floor(m.progressTowardBug)
-    m.bugs += int(newBugs)
-    m.progressTowardBug -= newBugs
-
-
-    m.usersPerSecondFromFeatures = float64(m.features)* USERS_PER_SECOND_PER_FEATURE
-    m.usersPerSecondFromMarketers = float64(m.marketers) * USERS_PER_SECOND_PER_MARKERTER
-    usersAddedPerSecond :=  m.usersPerSecondFromFeatures + m.usersPerSecondFromMarketers
-    m.progressTowardUser += usersAddedPerSecond
-    newUsers := math.Floor(m.progressTowardUser)
-    m.users += int(newUsers)
-    m.progressTowardUser -= newUsers
-
-    m.usersPerSecondFromBugs = float64(m.bugs) * USERS_PER_SECOND_PER_BUG
-    usersLostPerSecond := m.usersPerSecondFromBugs
-    m.progressTowardLostUser += usersLostPerSecond
-    lostUsers := math.Floor(m.progressTowardLostUser)
-    m.users -= int(lostUsers)
-    m.progressTowardUser -= lostUsers
+    if (m.LastSpike > 0) {
+        m.banner = fmt.Sprintf("★ GONE VIRAL! +%d users ★", m.LastSpike)
+        m.bannerStyle = viralStyle
+        m.bannerFrames = BANNER_FRAMES
+    } else if (m.LastSpike < 0) {
+        m.banner = fmt.Sprintf("✖ BACKLASH! Your bugs went viral. %d users ✖", m.LastSpike)
+        m.bannerStyle = backlashStyle
+        m.bannerFrames = BANNER_FRAMES
+    }
 
-    
-    m.cashPerSecond = CASH_PER_SECOND_PER_USER_PER_FEATURE * m.users * m.features
-    m.cash += m.cashPerSecond
-    m.cashParticlesVisible = min(int(math.Log2(float64(m.cashPerSecond))), len(m.cashParticles))
-
-    m.pricePerShare =   PRICE_PER_FEATURE * m.features +
-                        PRICE_PER_DEV * m.devs +
-                        PRICE_PER_BUG * m.bugs +
-                        PRICE_PER_USER * m.users +
-                        PRICE_PER_MARKETER * m.marketers
-        
-    if(m.cash > CASH_CAP){
-        m.scene = End
-        m.failureCause = "You've been crushed under the weight of your own success...\nA tragedy has befallen all mankind."
+    if (m.LastNews > 0) {
+        m.banner = fmt.Sprintf("▲ ANALYSTS LOVE YOU! Shares +%d ▲", m.LastNews)
+        m.bannerStyle = viralStyle
+        m.bannerFrames = BANNER_FRAMES
+    } else if (m.LastNews < 0) {
+        m.banner = fmt.Sprintf("▼ SHORT SELLER REPORT! Shares %d ▼", m.LastNews)
+        m.bannerStyle = backlashStyle
+        m.bannerFrames = BANNER_FRAMES
     }
 
-    if(m.pricePerShare < 0) {
+    m = m.fundingBanner(before)
+
+    if (m.Over()) {
         m.scene = End
-        m.failureCause = "Your enterprise has colapsed around you. A flash in the pan, nothing more."
+        m = m.askInitials()
+    } else if (m.PendingEvent != "") {
+        m = m.showEvent()
     }
 
     return m
@@ -224,12 +299,36 @@ func doFrameTick() tea.Cmd {
 }
 
 func onFrameTick(m model) model {
+    if (!m.gameTicking){
+        return m
+    }
+
     for i:=0;i<len(m.cashParticles);i++{
-        d20 := rand.Intn(20)
+        d20 := m.rng.Intn(20)
         x := m.cashParticles[i].x
         if (d20 < x - 1){ m.cashParticles[i].x -= 1 }
         if (d20 > x + 1){ m.cashParticles[i].x += 1 }
-        m.cashParticles[i].y = (m.cashParticles[i].y + rand.Intn(2)) % 20
+        // Money rains down into the pile while we earn, and floats back up
+        // out of it while we burn.
+        dy := m.rng.Intn(2)
+        if (m.CashPerSecond < 0) {
+            dy = -dy
+        }
+        m.cashParticles[i].y = (m.cashParticles[i].y + dy + 20) % 20
+    }
+
+    if (m.noticeFrames > 0) {
+        m.noticeFrames -= 1
+        if (m.noticeFrames == 0) {
+            m.notice = ""
+        }
+    }
+
+    if (m.bannerFrames > 0) {
+        m.bannerFrames -= 1
+        if (m.bannerFrames == 0) {
+            m.banner = ""
+        }
     }
     return m
 }
@@ -242,20 +341,34 @@ type devKeyMap struct {
     FireQA key.Binding
     HireMarketing key.Binding
     FireMarketing key.Binding
+    HireStrategist key.Binding
+    FireStrategist key.Binding
+    HireInfluencer key.Binding
+    FireInfluencer key.Binding
+    Raise key.Binding
+    Shop key.Binding
+    Refactor key.Binding
     FocusBugs key.Binding
     FocusNewFeatures key.Binding
     Help key.Binding
+    Charts key.Binding
+    Pause key.Binding
+    Slower key.Binding
+    Faster key.Binding
     Features key.Binding
     Bugs key.Binding
 }
 
 func (k devKeyMap) ShortHelp() []key.Binding {
-    return []key.Binding{k.Help}
+    return []key.Binding{k.Help, k.Shop, k.Charts, k.Pause}
 }
 func (k devKeyMap) FullHelp() [][]key.Binding {
     return [][]key.Binding{
-        {k.HireDev, k.FireDev, k.FocusBugs, k.FocusNewFeatures},
-        {k.Help},
+        {k.HireDev, k.FireDev, k.FocusBugs, k.FocusNewFeatures, k.Refactor},
+        {k.HireQA, k.FireQA, k.HireMarketing, k.FireMarketing},
+        {k.HireStrategist, k.FireStrategist, k.HireInfluencer, k.FireInfluencer},
+        {k.Raise, k.Shop, k.Slower, k.Faster},
+        {k.Help, k.Charts, k.Pause},
     }
 }
 
@@ -265,8 +378,8 @@ var devKeys = devKeyMap{
         key.WithHelp("h","hire dev"),
     ),
     HireQA: key.NewBinding(
-        key.WithKeys("y"),
-        key.WithHelp("y","hire qa"),
+        key.WithKeys("q", "y"),
+        key.WithHelp("q","hire qa"),
     ),
     HireMarketing: key.NewBinding(
         key.WithKeys("t"),
@@ -284,6 +397,34 @@ var devKeys = devKeyMap{
         key.WithKeys("e"),
         key.WithHelp("e","fire marketing"),
     ),
+    HireStrategist: key.NewBinding(
+        key.WithKeys("s"),
+        key.WithHelp("s","hire strategist"),
+    ),
+    FireStrategist: key.NewBinding(
+        key.WithKeys("a"),
+        key.WithHelp("a","fire strategist"),
+    ),
+    HireInfluencer: key.NewBinding(
+        key.WithKeys("i"),
+        key.WithHelp("i","hire influencer"),
+    ),
+    FireInfluencer: key.NewBinding(
+        key.WithKeys("u"),
+        key.WithHelp("u","fire influencer"),
+    ),
+    Raise: key.NewBinding(
+        key.WithKeys("g"),
+        key.WithHelp("g","sign term sheet"),
+    ),
+    Shop: key.NewBinding(
+        key.WithKeys("$"),
+        key.WithHelp("$","shop"),
+    ),
+    Refactor: key.NewBinding(
+        key.WithKeys("x"),
+        key.WithHelp("x","refactor sprint"),
+    ),
     FocusBugs: key.NewBinding(
         key.WithKeys("b"),
         key.WithHelp("b","focus bugs"),
@@ -294,19 +435,59 @@ var devKeys = devKeyMap{
     ),
     Features: key.NewBinding(
         key.WithKeys("{", "}","[","]","j","k"),
-        key.WithHelp("jkl;","make features"),
+        key.WithHelp("[]jk","write a feature"),
     ),
     Bugs: key.NewBinding(
         key.WithKeys("1","2","3","4"),
-        key.WithHelp("1234","fix bugs"),
+        key.WithHelp("1234","hunt a bug"),
     ),
     Help: key.NewBinding(
         key.WithKeys("?"),
         key.WithHelp("?", "help"),
     ),
+    Charts: key.NewBinding(
+        key.WithKeys("c"),
+        key.WithHelp("c", "charts"),
+    ),
+    Pause: key.NewBinding(
+        key.WithKeys("esc"),
+        key.WithHelp("esc", "pause"),
+    ),
+    Slower: key.NewBinding(
+        key.WithKeys(",", "<"),
+        key.WithHelp(",","slower"),
+    ),
+    Faster: key.NewBinding(
+        key.WithKeys(".", ">"),
+        key.WithHelp(".","faster"),
+    ),
 }
 
 
+// refactor starts a refactor sprint, leaving a notice on screen if we can't.
+func (m model) refactor() model {
+    s, err := sim.StartRefactor(m.balance, m.State)
+    if err != nil {
+        m.notice = "Can't refactor: " + err.Error()
+        m.noticeFrames = NOTICE_FRAMES
+        return m
+    }
+    m.State = s
+    return m
+}
+
+// hire tries to take on one r, leaving a notice on screen if we can't afford it.
+func (m model) hire(r sim.Role) model {
+    s, err := sim.Hire(m.balance, m.State, r)
+    if err != nil {
+        m.notice = "Can't hire: " + err.Error()
+        m.noticeFrames = NOTICE_FRAMES
+        return m
+    }
+    m.State = s
+    return m
+}
+
 func(m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
     switch msg := msg.(type) {
     case tea.WindowSizeMsg:
@@ -317,69 +498,180 @@ func(m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
         m.helpModel.Width = msg.Width
 
     case tea.KeyMsg: 
+        if (m.replaying) {
+            // The recording is at the wheel; all the player can do is leave.
+            if (msg.Type == tea.KeyCtrlC || key.Matches(msg, devKeys.Pause)) {
+                return m, tea.Quit
+            }
+            return m, nil
+        }
+        if (m.recorder != nil) {
+            m = m.recordErr(m.recorder.key(m.ticks, msg))
+        }
+        return m.updateKey(msg)
 
-        if msg.String() == tea.KeyEsc.String(){
-            return m, tea.Quit;
+    case GameTickMsg:
+        m, cmd := m.advance(time.Time(msg))
+        return m, tea.Batch(doGameTick(), cmd)
+
+    case savedMsg:
+        if (msg.err != nil) {
+            m.notice = "Couldn't save: " + msg.err.Error()
+            m.noticeFrames = NOTICE_FRAMES
+        } else if (m.scene != End) {
+            m.hasSave = true
+        }
+
+    case saveDiscardedMsg:
+        if (msg.err != nil) {
+            m.notice = "Couldn't clear the finished run's save: " + msg.err.Error()
+            m.noticeFrames = NOTICE_FRAMES
+        }
+
+    case scoresSavedMsg:
+        if (msg.err != nil) {
+            m.notice = "Couldn't save high scores: " + msg.err.Error()
+            m.noticeFrames = NOTICE_FRAMES
+        }
+
+    case FrameTickMsg:
+        m = onFrameTick(m)
+        return m, doFrameTick()
+
+    default:
+        if (m.enteringInitials) {
+            var cmd tea.Cmd
+            m.initials, cmd = m.initials.Update(msg)
+            return m, cmd
+        }
+        if (m.coding) {
+            var cmd tea.Cmd
+            m.code, cmd = m.code.Update(msg)
+            return m, cmd
+        }
+    }
+
+    return m, nil 
+}
+
+// updateKey handles a key press, whether it came from the keyboard or a
+// replay.
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+        if (m.scene == Paused) {
+            return m.updatePaused(msg)
+        }
+
+        if (m.scene == Start) {
+            return m.updateStart(msg)
+        }
+
+        if (m.scene == Choosing) {
+            return m.updateEvent(msg)
+        }
+
+        if (m.scene == End && m.enteringInitials) {
+            return m.updateInitials(msg)
+        }
+
+        if (m.scene == End) {
+            return m.updateEnd(msg)
+        }
+
+        if (m.shopOpen) {
+            return m.updateShop(msg)
+        }
+
+        if (m.coding) {
+            return m.updateCoding(msg)
         }
 
-        if (m.scene == Start){
-            m.scene = Game
+        if (m.hunting) {
+            return m.updateHunt(msg)
+        }
+
+        if key.Matches(msg, devKeys.Pause) {
+            if (m.scene == Game) {
+                return m.pause(), nil
+            }
+            return m, tea.Quit;
         }
 
         switch {
 
         case key.Matches(msg, devKeys.HireDev):
-            m.devs += 1
+            m = m.hire(sim.RoleDev)
 
         case key.Matches(msg, devKeys.HireQA):
-            m.qa += 1
+            m = m.hire(sim.RoleQA)
 
         case key.Matches(msg, devKeys.HireMarketing):
-            m.marketers += 1
+            m = m.hire(sim.RoleMarketer)
 
         case key.Matches(msg, devKeys.FireDev):
-            m.devs -= 1
+            m.State = sim.Fire(m.State, sim.RoleDev)
 
         case key.Matches(msg, devKeys.FireQA):
-            m.qa -= 1
+            m.State = sim.Fire(m.State, sim.RoleQA)
 
         case key.Matches(msg, devKeys.FireMarketing):
-            m.marketers -= 1
+            m.State = sim.Fire(m.State, sim.RoleMarketer)
+
+        case key.Matches(msg, devKeys.HireStrategist):
+            m = m.hire(sim.RoleStrategist)
+
+        case key.Matches(msg, devKeys.FireStrategist):
+            m.State = sim.Fire(m.State, sim.RoleStrategist)
+
+        case key.Matches(msg, devKeys.HireInfluencer):
+            m = m.hire(sim.RoleInfluencer)
+
+        case key.Matches(msg, devKeys.FireInfluencer):
+            m.State = sim.Fire(m.State, sim.RoleInfluencer)
+
+        case key.Matches(msg, devKeys.Raise):
+            m = m.raise()
+
+        case key.Matches(msg, devKeys.Shop):
+            m.shopOpen = true
+
+        case key.Matches(msg, devKeys.Refactor):
+            m = m.refactor()
 
         case key.Matches(msg, devKeys.FocusBugs):
-            m.devFocus = max(0, m.devFocus - 1)
+            m.State = sim.FocusBugs(m.State)
 
         case key.Matches(msg, devKeys.FocusNewFeatures):
-            m.devFocus = min(10, m.devFocus + 1)
+            m.State = sim.FocusFeatures(m.State)
       
         case key.Matches(msg, devKeys.Help):
             m.helpWindow = !m.helpWindow
 
-        case key.Matches(msg, devKeys.Features):
-            m.progressTowardFeature = 1.
+        case key.Matches(msg, devKeys.Charts):
+            m.chartWindow = !m.chartWindow
 
-        case key.Matches(msg, devKeys.Bugs):
-            m.bugs = max(0, m.bugs - 1)
-        }
+        case key.Matches(msg, devKeys.Slower):
+            m.speed = max(0, m.speed - 1)
 
+        case key.Matches(msg, devKeys.Faster):
+            m.speed = min(len(SPEEDS) - 1, m.speed + 1)
 
-    case GameTickMsg:
-        m = onGameTick(m)
-        return m, doGameTick()
+        case key.Matches(msg, devKeys.Features):
+            m = m.startCoding()
+            return m, textinput.Blink
 
-    case FrameTickMsg:
-        m = onFrameTick(m)
-        return m, doFrameTick()
-    }
+        case key.Matches(msg, devKeys.Bugs):
+            m = m.startHunt()
+        }
 
-    return m, nil 
+        return m, nil
 }
 
 var cashStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("35"))
+var lossStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
 var startupStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
 var cashParticleRunes = []rune{'◜','\'',',','◝','◃','"','◟','◞'}
-func randomRune() rune {
-    return cashParticleRunes[rand.Intn(len(cashParticleRunes))]
+func randomRune(rng *rand.Rand) rune {
+    return cashParticleRunes[rng.Intn(len(cashParticleRunes))]
 }
 
 
@@ -408,7 +700,7 @@ func (m model) CashWindow() string {
     
     style := startupStyle
     sec := time.Now().Unix()
-    if (m.cash > 900000 && sec % 2 == 0){
+    if (m.Cash > m.balance.CashCap * 9 / 20 && sec % 2 == 0){
         style = style.Foreground(lipgloss.Color("1"))
     } else {
         style = style.Foreground(lipgloss.Color("7"))
@@ -418,43 +710,63 @@ func (m model) CashWindow() string {
     s := cashTube + "\n" + startupBuilding
 
     l := float64(len(CashLevels))
-    g := float64(CASH_CAP)
+    g := float64(m.balance.CashCap)
     y := math.Pow(g, 1/l)
-    cashLog := math.Max(1.0,math.Log(float64(m.cash)))
+    cashLog := math.Max(1.0,math.Log(float64(max(1, m.Cash))))
     yLog := math.Log(y)
-    cashSize := int(cashLog / yLog / 2)
+    // Difficulty can scale a tiny cap down to nothing to climb toward.
+    cashSize := len(CashLevels) - 1
+    if (yLog > 0) {
+        cashSize = min(cashSize, int(cashLog / yLog / 2))
+    }
     cashPile := CashLevels[cashSize]
     s = PlaceOverlay(0+cashPile.x, 5+cashPile.y, cashStyle.Render(cashPile.view), s, false)
 
+    particleStyle := cashStyle
+    if (m.CashPerSecond < 0) {
+        particleStyle = lossStyle
+    }
     for i:=0;i<m.cashParticlesVisible;i++ {
-        s = PlaceOverlay(m.cashParticles[i].x, 3 + m.cashParticles[i].y, cashStyle.Render(string(randomRune())), s, false)
+        s = PlaceOverlay(m.cashParticles[i].x, 3 + m.cashParticles[i].y, particleStyle.Render(string(randomRune(m.rng))), s, false)
     }
 
     return s 
 }
 
+// adoption is the share of the market already using the product.
+func (m model) adoption() float64 {
+    if (m.Market == 0) {
+        return 0
+    }
+    return float64(m.Users) / float64(m.Market)
+}
+
 func (m model) TableView() string {
    
     cols := []table.Column{
         {Title: "", Width: 16},
         {Title: "", Width: 8},
-        {Title: "", Width: 16},
-        {Title: "", Width: 16},
-        {Title: "", Width: 16},
+        {Title: "", Width: 20},
+        {Title: "", Width: 20},
+        {Title: "", Width: 20},
     }
 
     rows := []table.Row{
-        {"Company Value", fmt.Sprintf("%v", m.pricePerShare)},
-        {"Cash", fmt.Sprintf("%v", m.cash), fmt.Sprintf("$%d/sec",m.cashPerSecond)},
+        {"Company Value", fmt.Sprintf("%v", m.PricePerShare), fmt.Sprintf("%+.0f/sec", m.PriceMomentum), fmt.Sprintf("%d fundamental", m.FundamentalValue), m.crashWarning()},
+        m.fundingRow(),
+        {"Cash", fmt.Sprintf("%v", m.Cash), money(m.CashPerSecond) + "/sec", money(m.RevenuePerSecond) + "/sec revenue", money(-m.SalariesPerSecond) + "/sec payroll"},
         {},
-        {"Users", fmt.Sprintf("%v", m.users), fmt.Sprintf("%.2f/sec", m.usersPerSecondFromFeatures + m.usersPerSecondFromMarketers - m.usersPerSecondFromBugs)},
+        {"Users", fmt.Sprintf("%v", m.Users), fmt.Sprintf("%.2f/sec", m.UsersPerSecondFromFeatures + m.UsersPerSecondFromMarketers - m.UsersPerSecondFromBugs)},
+        {"Market", fmt.Sprintf("%v", m.Market), fmt.Sprintf("%.2f/sec", m.MarketPerSecond), fmt.Sprintf("%.0f%% adopted", m.adoption() * 100)},
         {},
-        {"Features", fmt.Sprintf("%v", m.features), fmt.Sprintf("%.2f Users/sec",m.usersPerSecondFromFeatures), fmt.Sprintf("%.2f Bugs/sec",m.bugsPerSecondPerFeature)},
-        {"Bugs", fmt.Sprintf("%v", m.bugs), fmt.Sprintf("%.2f Users/sec", -m.usersPerSecondFromBugs)},
+        {"Features", fmt.Sprintf("%v", m.Features), fmt.Sprintf("%.2f Users/sec",m.UsersPerSecondFromFeatures), fmt.Sprintf("%.2f Bugs/sec",m.BugsPerSecondPerFeature)},
+        {"Bugs", fmt.Sprintf("%v", m.Bugs), fmt.Sprintf("%.2f Users/sec", -m.UsersPerSecondFromBugs), fmt.Sprintf("%.2f Fixes/sec", m.BugsFixedPerSecond)},
         {},
-        {"Devs", fmt.Sprintf("%v",m.devs),fmt.Sprintf("%.2f Features/sec", m.featuresPerSecond), fmt.Sprintf("%.2f Bugs/sec",m.bugsPerSecondPerDev),fmt.Sprintf("%d $/sec", m.devs * DEV_SALARY_PER_SECOND)},
-        {"QA", fmt.Sprintf("%v", m.qa)},
-        {"Marketers", fmt.Sprintf("%v", m.marketers)},
+        {"Devs", fmt.Sprintf("%v",m.Devs),fmt.Sprintf("%.2f Features/sec", m.FeaturesPerSecond), fmt.Sprintf("%.2f Bugs/sec",m.BugsPerSecondPerDev),money(-m.Devs * m.balance.Salary(sim.RoleDev)) + "/sec"},
+        {"QA", fmt.Sprintf("%v", m.QA), fmt.Sprintf("%.2f Fixes/sec", m.BugsFixedPerSecondByQA), fmt.Sprintf("-%.0f%% Features", m.FeatureDragFromQA * 100), money(-m.QA * m.balance.Salary(sim.RoleQA)) + "/sec"},
+        {"Marketers", fmt.Sprintf("%v", m.Marketers), "", "", money(-m.Marketers * m.balance.Salary(sim.RoleMarketer)) + "/sec"},
+        {"Strategists", fmt.Sprintf("%v", m.Strategists), fmt.Sprintf("%.2f Market/sec", m.MarketPerSecond), "", money(-m.Strategists * m.balance.Salary(sim.RoleStrategist)) + "/sec"},
+        {"Influencers", fmt.Sprintf("%v", m.Influencers), fmt.Sprintf("%.0f%% Viral/sec", m.ViralChancePerSecond * 100), "", money(-m.Influencers * m.balance.Salary(sim.RoleInfluencer)) + "/sec"},
     }
 
     t := table.New(
@@ -462,10 +774,63 @@ func (m model) TableView() string {
         table.WithColumns(cols),
     )
 
-    return t.View()
+    view := t.View()
+    // The charts go in place of the table's empty rows, under its numbers
+    // rather than over them.
+    if (m.chartWindow) {
+        t.SetHeight(len(rows) + 1)
+        view = t.View() + "\n" + m.ChartView()
+    }
+
+    return view + "\n" + m.DevFocusView() + "\n" + m.TechDebtView()
+}
+
+// money formats dollars with the sign in front, so a loss reads -$3 rather
+// than $-3.
+func money(dollars int) string {
+    if (dollars < 0) {
+        return fmt.Sprintf("-$%d", -dollars)
+    }
+    return fmt.Sprintf("$%d", dollars)
+}
+
+// crashWarning counts down to collapse while the share price is under water.
+func (m model) crashWarning() string {
+    if (m.CrashSeconds == 0) {
+        return ""
+    }
+    return fmt.Sprintf("CRASH %.0f/%.0fs", m.CrashSeconds, m.balance.CollapseSeconds)
+}
+
+// DevFocusView draws the bug/feature slider. It lives outside the table since
+// table cells are truncated without regard for the bar's colour codes.
+func (m model) DevFocusView() string {
+    bar := m.devFocusProgress.ViewAs(float64(m.DevFocus) / sim.MAX_DEV_FOCUS)
+    return fmt.Sprintf(" %-16s bugs %s features  %d/%d", "Dev Focus", bar, m.DevFocus, sim.MAX_DEV_FOCUS)
+}
+
+
+var refactorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("35")).Bold(true)
+
+// TechDebtView draws the tech debt meter, and the sprint paying it down if
+// there is one.
+func (m model) TechDebtView() string {
+    bar := m.devFocusProgress.ViewAs(float64(m.CopyPasteModifier) / sim.MAX_TECH_DEBT)
+    view := fmt.Sprintf(" %-16s %s %d/%d  %+.1f/sec", "Tech Debt", bar, m.CopyPasteModifier, sim.MAX_TECH_DEBT, m.TechDebtPerSecond)
+    if (m.RefactorSeconds > 0) {
+        view += "  " + refactorStyle.Render(fmt.Sprintf("REFACTORING %.0fs", m.RefactorSeconds))
+    }
+    return view
 }
 
 
+var viralStyle = lipgloss.NewStyle().Bold(true).Padding(0, 1).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("213"))
+var backlashStyle = viralStyle.Background(lipgloss.Color("1")).Foreground(lipgloss.Color("15"))
+
+func (m model) BannerView() string {
+    return m.bannerStyle.Render(m.banner)
+}
+
 var devBorder = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("63"));
 
 func (m model) DevWindowView() string {
@@ -481,6 +846,8 @@ func maxWidth(s []string) int {
     return maxW
 }
 
+var noticeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+
 var centerStyle = lipgloss.NewStyle().Align(lipgloss.Center, lipgloss.Center)
 
 func (m model) View() string {
@@ -490,8 +857,12 @@ func (m model) View() string {
         return viewStyle.Render(m.StartView())
     case Game:
         return viewStyle.Render(m.GameView())
+    case Paused:
+        return viewStyle.Render(m.PausedView())
     case End:
         return viewStyle.Render(m.EndView())
+    case Choosing:
+        return viewStyle.Render(m.ChoosingView())
     }
     return "State not found"
 }
@@ -511,6 +882,26 @@ func (m model) GameView() string {
     w := maxWidth(strings.Split(cashView, "\n"))
     base = PlaceOverlay(m.width-w-1, 1, cashView, base, false)
 
+    if (m.banner != "") {
+        banner := m.BannerView()
+        width := maxWidth(strings.Split(banner, "\n"))
+        base = PlaceOverlay(m.width/2 - width/2, 1, banner, base, false)
+    }
+
+    if (m.shopOpen) {
+        shop := m.ShopView()
+        lines := strings.Split(shop, "\n")
+        width := maxWidth(lines)
+        base = PlaceOverlay(m.width/2 - width/2, m.height/2 - len(lines)/2, shop, base, true)
+    }
+
+    if (m.coding || m.hunting) {
+        game := m.MinigameView()
+        lines := strings.Split(game, "\n")
+        width := maxWidth(lines)
+        base = PlaceOverlay(m.width/2 - width/2, m.height/2 - len(lines)/2, game, base, true)
+    }
+
     if (m.helpWindow) {
         devOverlay := m.DevWindowView()
         lines := strings.Split(devOverlay, "\n")
@@ -519,11 +910,22 @@ func (m model) GameView() string {
     }
 
     shortHelp := m.helpModel.ShortHelpView(devKeys.ShortHelp())
-    base += "\n" + shortHelp + "\n"
+    base += "\n" + m.SpeedView() + "   " + shortHelp + "\n"
+    base += noticeStyle.Render(m.notice) + "\n"
     base += "\n--" + m.debug + "--\n"
     return base 
 }
 
+var speedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("63"))
+
+func (m model) SpeedView() string {
+    speed := SPEEDS[m.speed]
+    if (speed == 0) {
+        return speedStyle.Render("⏸ stopped")
+    }
+    return speedStyle.Render(fmt.Sprintf("▶ %dx", speed))
+}
+
 func (m model) StartView() string {
     style := baseScreenStyle(m)
     return style.Align(lipgloss.Center, lipgloss.Center).Render(`
@@ -533,17 +935,85 @@ func (m model) StartView() string {
    ██║   ██╔══██║██╔══╝      ╚════██║   ██║   ██╔══██║██╔══██╗   ██║   ██║   ██║██╔═══╝     ██║    ██║   ██║╚██╔╝██║ ██║
    ██║   ██║  ██║███████╗    ███████║   ██║   ██║  ██║██║  ██║   ██║   ╚██████╔╝██║         ╚██╗   ██║   ██║ ╚═╝ ██║██╔╝
    ╚═╝   ╚═╝  ╚═╝╚══════╝    ╚══════╝   ╚═╝   ╚═╝  ╚═╝╚═╝  ╚═╝   ╚═╝    ╚═════╝ ╚═╝          ╚═╝   ╚═╝   ╚═╝     ╚═╝╚═╝ 
-`)
+` + m.StartMenuView())
 }
 
-func (m model) EndView() string {
-    style := baseStyle
-    base := style.Width(min(m.width,200)).Height(min(m.height,20)).Align(lipgloss.Center,lipgloss.Center).Render(m.failureCause)
-    return base
+
+// newSeed picks a seed for a run nobody asked to reproduce.
+func newSeed() int64 {
+    return time.Now().UnixNano()
 }
 
 func main() {
-    p := tea.NewProgram(initialModel())
+    if (len(os.Args) > 1 && os.Args[1] == "sim") {
+        os.Exit(runSim(os.Args[2:], os.Stdout, os.Stderr))
+    }
+
+    load := flag.Bool("load", false, "resume the last saved game")
+    balancePath := flag.String("balance", "", "read tuning numbers from this JSON file")
+    seed := flag.Int64("seed", 0, "random seed, to replay a run exactly (default: pick one)")
+    record := flag.String("record", "", "record key presses to this replay file (default: last.replay in the config dir)")
+    replay := flag.String("replay", "", "play back a replay file instead of reading the keyboard")
+    headless := flag.Bool("headless", false, "with --replay, play back without a terminal and print the result")
+    flag.Parse()
+
+    if (*replay != "") {
+        h, events, err := readReplay(*replay)
+        if err != nil {
+            fmt.Printf("Couldn't read the replay: %v\n", err)
+            os.Exit(1)
+        }
+        m := replayModel(h, events)
+        if (*headless) {
+            runHeadlessReplay(m, os.Stdout)
+            return
+        }
+        run(m)
+        return
+    }
+
+    balance := sim.DefaultBalance()
+    if (*balancePath != "") {
+        b, err := sim.LoadBalance(*balancePath)
+        if err != nil {
+            fmt.Printf("Couldn't read the balance sheet: %v\n", err)
+            os.Exit(1)
+        }
+        balance = b
+    }
+
+    seedFixed := true
+    if (*seed == 0) {
+        *seed = newSeed()
+        seedFixed = false
+    }
+
+    m := initialModel(balance, *seed)
+    m.seedFixed = seedFixed
+    if (*load) {
+        s, err := loadGame()
+        if err != nil {
+            fmt.Printf("Couldn't load your startup: %v\n", err)
+            os.Exit(1)
+        }
+        m = m.withSave(s)
+    }
+
+    if (*record == "") {
+        if path, err := replayPath(); err == nil {
+            *record = path
+        }
+    }
+    if (*record != "") {
+        m.recorder = newRecorder(*record)
+        m = m.startRecording()
+    }
+
+    run(m)
+}
+
+func run(m model) {
+    p := tea.NewProgram(m)
     if _, err := p.Run(); err != nil {
         fmt.Printf("Alas, there has been an error: %v", err)
         os.Exit(1)
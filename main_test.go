@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"theStartupTM/sim"
+)
+
+// advancingModel is a run in progress whose clock last woke at start, with
+// nothing random that could interrupt it with an event.
+func advancingModel(start time.Time) model {
+	b := sim.DefaultBalance()
+	b.EventChancePerSecond = 0
+	m := initialModel(b, 1).startGame()
+	m.lastTick = start
+	return m
+}
+
+func TestAdvance(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		setup     func(m *model)
+		gap       time.Duration
+		wantTicks int
+		wantSince time.Duration
+	}{
+		{
+			name:      "one step's worth",
+			gap:       SIM_STEP,
+			wantTicks: 1,
+		},
+		{
+			name:      "not yet a step",
+			gap:       SIM_STEP / 2,
+			wantSince: SIM_STEP / 2,
+		},
+		{
+			name:      "leftover time carries over",
+			setup:     func(m *model) { m.sinceStep = SIM_STEP * 6 / 10 },
+			gap:       SIM_STEP / 2,
+			wantTicks: 1,
+			wantSince: SIM_STEP / 10,
+		},
+		{
+			name:      "a long gap catches up only so far",
+			gap:       time.Hour,
+			wantTicks: MAX_CATCH_UP_STEPS,
+		},
+		{
+			name:      "paused time doesn't count",
+			setup:     func(m *model) { m.scene = Paused },
+			gap:       time.Hour,
+			wantTicks: 0,
+		},
+		{
+			name:      "the first wake-up only starts the clock",
+			setup:     func(m *model) { m.lastTick = time.Time{} },
+			gap:       time.Hour,
+			wantTicks: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := advancingModel(start)
+			if tt.setup != nil {
+				tt.setup(&m)
+			}
+			m, _ = m.advance(start.Add(tt.gap))
+			if m.ticks != tt.wantTicks {
+				t.Errorf("ticks = %d, want %d", m.ticks, tt.wantTicks)
+			}
+			if m.sinceStep != tt.wantSince {
+				t.Errorf("sinceStep = %v, want %v", m.sinceStep, tt.wantSince)
+			}
+			if !m.lastTick.Equal(start.Add(tt.gap)) {
+				t.Errorf("lastTick = %v, want the wake-up time %v", m.lastTick, start.Add(tt.gap))
+			}
+		})
+	}
+}
+
+func TestAdvanceKeepsPace(t *testing.T) {
+	// Waking up four times a step for a minute runs a step a second, however
+	// the wake-ups fall.
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := advancingModel(start)
+	now := start
+	for now.Before(start.Add(time.Minute)) {
+		now = now.Add(SIM_STEP/time.Duration(POLLS_PER_STEP) + time.Millisecond)
+		m, _ = m.advance(now)
+	}
+	want := int(now.Sub(start) / SIM_STEP)
+	if m.ticks != want {
+		t.Errorf("ticks = %d after %v, want %d", m.ticks, now.Sub(start), want)
+	}
+}
+
+func TestAdvanceSpeeds(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		speed     int
+		gap       time.Duration
+		wantTicks int
+		wantSince time.Duration
+	}{
+		{speed: 0, gap: time.Hour, wantTicks: 0},
+		{speed: 1, gap: SIM_STEP, wantTicks: 1},
+		{speed: 2, gap: SIM_STEP / 2, wantTicks: 1},
+		{speed: 5, gap: SIM_STEP * 3 / 10, wantTicks: 1, wantSince: SIM_STEP / 2},
+		{speed: 10, gap: SIM_STEP, wantTicks: 10},
+		// The catch-up cap scales with the speed, so a slow wake-up at 10x
+		// isn't mistaken for a suspended process.
+		{speed: 10, gap: time.Duration(MAX_CATCH_UP_STEPS) * SIM_STEP, wantTicks: 10 * MAX_CATCH_UP_STEPS},
+		{speed: 10, gap: time.Hour, wantTicks: 10 * MAX_CATCH_UP_STEPS},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%dx for %v", tt.speed, tt.gap), func(t *testing.T) {
+			m := advancingModel(start)
+			m.speed = slices.Index(SPEEDS, tt.speed)
+			m, _ = m.advance(start.Add(tt.gap))
+			if m.ticks != tt.wantTicks {
+				t.Errorf("ticks = %d, want %d", m.ticks, tt.wantTicks)
+			}
+			if m.sinceStep != tt.wantSince {
+				t.Errorf("sinceStep = %v, want %v", m.sinceStep, tt.wantSince)
+			}
+		})
+	}
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		dollars int
+		want    string
+	}{
+		{0, "$0"},
+		{3, "$3"},
+		{-3, "-$3"},
+		{-123456, "-$123456"},
+	}
+	for _, tt := range tests {
+		if got := money(tt.dollars); got != tt.want {
+			t.Errorf("money(%d) = %q, want %q", tt.dollars, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"theStartupTM/sim"
+)
+
+// Shipping a feature by hand means typing out one of these.
+var snippets = []string{
+	"if err != nil { return err }",
+	"users = append(users, u)",
+	"defer db.Close()",
+	"go func() { ship(feature) }()",
+	"seen := make(map[string]bool)",
+	"for _, bug := range bugs {}",
+	`log.Println("works on my machine")`,
+	"return features + 1, nil",
+}
+
+// Fixing a bug by hand means spotting which of a few of these lines has the
+// typo in it.
+var codeLines = []struct{ ok, typo string }{
+	{"if err != nil {", "if err != nill {"},
+	{"return nil, err", "retrun nil, err"},
+	{"for i := 0; i < n; i++ {", "for i := 0; i < n; i+ {"},
+	{"defer f.Close()", "defer f.Cloes()"},
+	{"users = append(users, u)", "users = apend(users, u)"},
+	{"cash += revenue - payroll", "cash += revenue - payrol"},
+	{"ctx, cancel := context.WithCancel(ctx)", "ctx, cancel = context.WithCancel(ctx)"},
+	{`fmt.Println("shipped!")`, `fmt.Println("shipped!)`},
+}
+
+// HUNT_LINES is how many lines a bug hunt shows; one per bug key.
+const HUNT_LINES = 4
+
+// puzzleRand deals the next puzzle. It's worked out from the run rather than
+// kept in the model, so a replay, even one picked up from a save, is dealt
+// the same puzzles.
+func (m model) puzzleRand() sim.Rand {
+	done := m.Stats.FeaturesShipped + m.Stats.BugsFixed
+	return sim.NewRand(m.Seed ^ int64(m.ticks)<<32 ^ int64(done))
+}
+
+// startCoding asks the player to type out a snippet to ship a feature.
+func (m model) startCoding() model {
+	r := m.puzzleRand()
+	m.snippet = snippets[r.Intn(len(snippets))]
+	m.code = textinput.New()
+	m.code.Prompt = "> "
+	m.code.CharLimit = len(m.snippet) * 2
+	m.code.Width = len(m.snippet) + 1
+	m.code.Focus()
+	m.coding = true
+	return m
+}
+
+// startHunt shows a few lines of code, one with a typo, for the player to
+// find with the bug keys.
+func (m model) startHunt() model {
+	if m.Bugs == 0 {
+		m.notice = "No bugs to fix"
+		m.noticeFrames = NOTICE_FRAMES
+		return m
+	}
+	r := m.puzzleRand()
+	picks := make([]int, len(codeLines))
+	for i := range picks {
+		picks[i] = i
+	}
+	m.huntLines = make([]string, HUNT_LINES)
+	for i := range m.huntLines {
+		j := i + r.Intn(len(picks)-i)
+		picks[i], picks[j] = picks[j], picks[i]
+		m.huntLines[i] = codeLines[picks[i]].ok
+	}
+	m.huntTypo = r.Intn(HUNT_LINES)
+	m.huntLines[m.huntTypo] = codeLines[picks[m.huntTypo]].typo
+	m.hunting = true
+	return m
+}
+
+var codingKeys = struct {
+	Submit key.Binding
+	Cancel key.Binding
+}{
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "ship it"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "give up"),
+	),
+}
+
+// The game keeps running while the player types.
+func (m model) updateCoding(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, codingKeys.Submit):
+		m.coding = false
+		if strings.TrimSpace(m.code.Value()) != m.snippet {
+			m.notice = "Typo! The build is broken and the feature didn't ship"
+			m.noticeFrames = NOTICE_FRAMES
+			return m, nil
+		}
+		m.State = sim.ShipFeatureByHand(m.balance, m.State)
+		m.notice = "Feature shipped!"
+		m.noticeFrames = NOTICE_FRAMES
+		return m, nil
+
+	case key.Matches(msg, codingKeys.Cancel):
+		m.coding = false
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.code, cmd = m.code.Update(msg)
+	return m, cmd
+}
+
+var huntKeys = struct {
+	Pick   key.Binding
+	Cancel key.Binding
+}{
+	Pick: key.NewBinding(
+		key.WithKeys("1", "2", "3", "4"),
+		key.WithHelp("1-4", "that line"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "give up"),
+	),
+}
+
+func (m model) updateHunt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, huntKeys.Pick):
+		m.hunting = false
+		if int(msg.Runes[0]-'1') != m.huntTypo {
+			m.notice = "That line was fine. The bug got away"
+			m.noticeFrames = NOTICE_FRAMES
+			return m, nil
+		}
+		m.State = sim.FixBugByHand(m.State)
+		m.notice = "Bug squashed!"
+		m.noticeFrames = NOTICE_FRAMES
+
+	case key.Matches(msg, huntKeys.Cancel):
+		m.hunting = false
+	}
+	return m, nil
+}
+
+var snippetStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("35"))
+
+// MinigameView is the overlay for whichever puzzle is open.
+func (m model) MinigameView() string {
+	var body string
+	if m.coding {
+		body = "SHIP A FEATURE  type it out exactly\n\n  " +
+			snippetStyle.Render(m.snippet) + "\n" +
+			m.code.View() + "\n\n" +
+			m.helpModel.ShortHelpView([]key.Binding{codingKeys.Submit, codingKeys.Cancel})
+	} else {
+		lines := make([]string, len(m.huntLines))
+		for i, line := range m.huntLines {
+			lines[i] = fmt.Sprintf("%d  %s", i+1, snippetStyle.Render(line))
+		}
+		body = "FIX A BUG  which line has the typo?\n\n" +
+			strings.Join(lines, "\n") + "\n\n" +
+			m.helpModel.ShortHelpView([]key.Binding{huntKeys.Pick, huntKeys.Cancel})
+	}
+	return devBorder.Render(body)
+}
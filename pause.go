@@ -0,0 +1,140 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type pauseItem int
+
+const (
+	PauseResume pauseItem = iota
+	PauseRestart
+	PauseQuit
+)
+
+var pauseItemLabels = []string{"Resume", "Restart", "Quit"}
+
+type pauseKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Select key.Binding
+	Resume key.Binding
+}
+
+func (k pauseKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Select, k.Resume}
+}
+
+func (k pauseKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var pauseKeys = pauseKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Select: key.NewBinding(
+		key.WithKeys("enter", " "),
+		key.WithHelp("enter", "select"),
+	),
+	Resume: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "resume"),
+	),
+}
+
+// pause freezes the game and opens the pause menu.
+func (m model) pause() model {
+	m.scene = Paused
+	m.gameTicking = false
+	m.pauseCursor = PauseResume
+	return m
+}
+
+func (m model) resume() model {
+	m.scene = Game
+	m.gameTicking = true
+	return m
+}
+
+// restart throws away the current run, keeping only what we know about the
+// terminal, the last difficulty picked and a seed given with --seed.
+func (m model) restart() model {
+	seed := m.Seed
+	if !m.seedFixed {
+		seed = newSeed()
+	}
+	n := initialModel(m.baseBalance, seed)
+	n.difficulty = m.difficulty
+	n.seedFixed = m.seedFixed
+	n.width = m.width
+	n.height = m.height
+	n.windowWidth = m.windowWidth
+	n.windowHeight = m.windowHeight
+	n.helpModel.Width = m.helpModel.Width
+	// The leaderboard may still be on its way to disk.
+	n.scores = m.scores
+	n.recorder = m.recorder
+	n.replaying = m.replaying
+	// The save on disk may be on its way out with the run that just ended.
+	n.hasSave = m.hasSave && !m.replaying
+	return n.startRecording()
+}
+
+func (m model) updatePaused(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, pauseKeys.Up):
+		m.pauseCursor = (m.pauseCursor + pauseItem(len(pauseItemLabels)) - 1) % pauseItem(len(pauseItemLabels))
+
+	case key.Matches(msg, pauseKeys.Down):
+		m.pauseCursor = (m.pauseCursor + 1) % pauseItem(len(pauseItemLabels))
+
+	case key.Matches(msg, pauseKeys.Resume):
+		return m.resume(), nil
+
+	case key.Matches(msg, pauseKeys.Select):
+		switch m.pauseCursor {
+		case PauseResume:
+			return m.resume(), nil
+		case PauseRestart:
+			return m.restart(), nil
+		case PauseQuit:
+			return m, tea.Sequence(m.save(), tea.Quit)
+		}
+	}
+	return m, nil
+}
+
+var pauseBorder = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("63")).Padding(0, 2)
+var pauseSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("35")).Bold(true)
+
+func (m model) PauseMenuView() string {
+	items := make([]string, len(pauseItemLabels))
+	for i, label := range pauseItemLabels {
+		if pauseItem(i) == m.pauseCursor {
+			items[i] = pauseSelectedStyle.Render("> " + label)
+		} else {
+			items[i] = "  " + label
+		}
+	}
+	menu := "PAUSED\n\n" + strings.Join(items, "\n") + "\n\n" + m.helpModel.ShortHelpView(pauseKeys.ShortHelp())
+	return pauseBorder.Render(menu)
+}
+
+// PausedView draws the pause menu on top of the frozen game.
+func (m model) PausedView() string {
+	base := m.GameView()
+	menu := m.PauseMenuView()
+	lines := strings.Split(menu, "\n")
+	width := maxWidth(lines)
+	return PlaceOverlay(m.width/2-width/2, m.height/2-len(lines)/2, menu, base, true)
+}
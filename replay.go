@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"theStartupTM/sim"
+)
+
+// A replay file is JSON lines: a replayHeader, then one replayEvent per key
+// the player pressed, tagged with the game tick it landed on. Together with
+// the seed in the header that's enough to play the run back exactly.
+
+// REPLAY_VERSION is bumped whenever the replay format changes incompatibly.
+const REPLAY_VERSION = 7
+
+type replayHeader struct {
+	Version int         `json:"version"`
+	Seed    int64       `json:"seed"`
+	Balance sim.Balance `json:"balance"`
+	// Save is where the run picked up from, if it was loaded mid-game.
+	Save *saveFile `json:"save,omitempty"`
+}
+
+type replayEvent struct {
+	Tick int        `json:"tick"`
+	Key  *replayKey `json:"key,omitempty"`
+	// End marks the tick the run ended on.
+	End bool `json:"end,omitempty"`
+}
+
+type replayKey struct {
+	Type  tea.KeyType `json:"type"`
+	Runes string      `json:"runes,omitempty"`
+	Alt   bool        `json:"alt,omitempty"`
+}
+
+func (k replayKey) msg() tea.KeyMsg {
+	return tea.KeyMsg{Type: k.Type, Runes: []rune(k.Runes), Alt: k.Alt}
+}
+
+// replayPath is where the current run is recorded unless --record says
+// otherwise.
+func replayPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "theStartupTM", "last.replay"), nil
+}
+
+// recorder writes the keys of the run in progress to a replay file. Each run
+// starts the file over, so it always holds the latest one.
+type recorder struct {
+	path string
+	f    *os.File
+	enc  *json.Encoder
+}
+
+func newRecorder(path string) *recorder {
+	return &recorder{path: path}
+}
+
+func (r *recorder) start(h replayHeader) error {
+	if r.f != nil {
+		r.f.Close()
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(r.path)
+	if err != nil {
+		return err
+	}
+	r.f = f
+	r.enc = json.NewEncoder(f)
+	return r.enc.Encode(h)
+}
+
+func (r *recorder) key(tick int, msg tea.KeyMsg) error {
+	return r.enc.Encode(replayEvent{
+		Tick: tick,
+		Key:  &replayKey{Type: msg.Type, Runes: string(msg.Runes), Alt: msg.Alt},
+	})
+}
+
+func (r *recorder) end(tick int) error {
+	return r.enc.Encode(replayEvent{Tick: tick, End: true})
+}
+
+// startRecording begins a new replay from wherever the model is now.
+func (m model) startRecording() model {
+	if m.recorder == nil {
+		return m
+	}
+	h := replayHeader{Version: REPLAY_VERSION, Seed: m.Seed, Balance: m.baseBalance}
+	if m.scene != Start {
+		s := m.snapshot()
+		h.Save = &s
+	}
+	return m.recordErr(m.recorder.start(h))
+}
+
+// recordErr stops recording after the first failure rather than nagging on
+// every key press.
+func (m model) recordErr(err error) model {
+	if err != nil {
+		m.recorder = nil
+		m.notice = "Stopped recording: " + err.Error()
+		m.noticeFrames = NOTICE_FRAMES
+	}
+	return m
+}
+
+func readReplay(path string) (replayHeader, []replayEvent, error) {
+	var h replayHeader
+	var events []replayEvent
+
+	f, err := os.Open(path)
+	if err != nil {
+		return h, nil, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	if err := dec.Decode(&h); err != nil {
+		return h, nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if h.Version != REPLAY_VERSION {
+		return h, nil, fmt.Errorf("%s is replay version %d, want %d", path, h.Version, REPLAY_VERSION)
+	}
+	for {
+		var e replayEvent
+		err := dec.Decode(&e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return h, nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		events = append(events, e)
+	}
+	return h, events, nil
+}
+
+// replayModel sets up a model that plays back the given recording instead of
+// listening to the keyboard.
+func replayModel(h replayHeader, events []replayEvent) model {
+	m := initialModel(h.Balance, h.Seed)
+	m.seedFixed = true
+	m.hasSave = false
+	if h.Save != nil {
+		m = m.withSave(*h.Save)
+	}
+	m.replaying = true
+	m.replay = events
+	return m
+}
+
+// feedReplay presses every recorded key that was pressed before the current
+// tick ended, in the order they were pressed. Keys after the end of the run
+// went to the end screen, signing the leaderboard or restarting, so the
+// replay stops there and leaves the viewer on the run it recorded.
+func (m model) feedReplay() (model, tea.Cmd) {
+	var cmds []tea.Cmd
+	for len(m.replay) > 0 && m.replay[0].Tick <= m.ticks {
+		e := m.replay[0]
+		m.replay = m.replay[1:]
+		if e.End {
+			m.replay = nil
+			break
+		}
+		if e.Key == nil {
+			continue
+		}
+		next, cmd := m.updateKey(e.Key.msg())
+		m = next.(model)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// runHeadlessReplay plays a recording as fast as possible without a terminal
+// and reports how the run went.
+func runHeadlessReplay(m model, w io.Writer) {
+	lastTick := 0
+	if len(m.replay) > 0 {
+		lastTick = m.replay[len(m.replay)-1].Tick
+	}
+	for {
+		m, _ = m.feedReplay()
+		// Nothing left that could move the game along.
+		if m.scene != Game || m.ticks >= lastTick {
+			break
+		}
+		m = onGameTick(m)
+	}
+
+	cause := m.FailureCause
+	if cause == "" {
+		cause = "still running"
+	}
+	fmt.Fprintf(w, "seed:            %d\n", m.Seed)
+	fmt.Fprintf(w, "difficulty:      %v\n", m.difficulty)
+	fmt.Fprintf(w, "ticks:           %d\n", m.ticks)
+	fmt.Fprintf(w, "end:             %s\n", strings.ReplaceAll(cause, "\n", " "))
+	fmt.Fprintf(w, "cash:            %d\n", m.Cash)
+	fmt.Fprintf(w, "price per share: %d\n", m.PricePerShare)
+}
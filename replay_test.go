@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"theStartupTM/sim"
+)
+
+// TestReplayRoundTrip records a run played through the keyboard, reads the
+// recording back and checks it plays out to the very same company.
+func TestReplayRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "run.replay")
+
+	m := initialModel(sim.DefaultBalance(), 42)
+	m.recorder = newRecorder(path)
+	m = m.startRecording()
+	defer func() {
+		if m.recorder != nil {
+			m.recorder.f.Close()
+		}
+	}()
+
+	press := func(msg tea.KeyMsg) {
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+	runes := func(s string) {
+		for _, r := range s {
+			press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	for i := 0; i < 1000 && m.ticks < 300 && m.scene != End; i++ {
+		switch {
+		case m.scene == Choosing:
+			press(tea.KeyMsg{Type: tea.KeyEnter})
+		case m.ticks%7 == 3 && m.Bugs > 0:
+			runes("1")
+			if m.hunting {
+				runes(fmt.Sprint(m.huntTypo + 1))
+			}
+		case m.Cash >= m.balance.DevSigningCost && m.Devs < 5:
+			runes("h")
+		case m.ticks%2 == 0:
+			runes("j")
+			runes(m.snippet)
+			press(tea.KeyMsg{Type: tea.KeyEnter})
+		}
+		m = onGameTick(m)
+	}
+	if m.recorder == nil {
+		t.Fatalf("recording stopped: %s", m.notice)
+	}
+	if !m.Over() || m.Stats.FeaturesShipped == 0 || m.Devs == 0 {
+		t.Fatalf("the scripted run didn't play out to an ending: %+v", m.State)
+	}
+	// The game marks the end of the run, then the player signs the
+	// leaderboard. The replay has to stop before those keys reach an end
+	// screen that isn't asking for initials.
+	m = m.recordErr(m.recorder.end(m.ticks))
+	m.recordedEnd = true
+	if !m.enteringInitials {
+		t.Fatalf("the run didn't make the empty leaderboard")
+	}
+	runes("BOB")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.scoreRank != 0 {
+		t.Fatalf("signing the leaderboard ranked %d, want 0", m.scoreRank)
+	}
+
+	h, events, err := readReplay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	runHeadlessReplay(replayModel(h, events), &out)
+
+	cause := m.FailureCause
+	if cause == "" {
+		cause = "still running"
+	}
+	for _, want := range []string{
+		fmt.Sprintf("seed:            %d\n", m.Seed),
+		fmt.Sprintf("ticks:           %d\n", m.ticks),
+		fmt.Sprintf("end:             %s\n", strings.ReplaceAll(cause, "\n", " ")),
+		fmt.Sprintf("cash:            %d\n", m.Cash),
+		fmt.Sprintf("price per share: %d\n", m.PricePerShare),
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("replay reported\n%s\nwant a line %q", out.String(), want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"theStartupTM/sim"
+)
+
+// SAVE_VERSION is bumped whenever saveFile changes shape incompatibly.
+const SAVE_VERSION = 5
+
+// AUTOSAVE_TICKS is how many game ticks pass between autosaves.
+var AUTOSAVE_TICKS = 10
+
+// saveFile is what goes to disk: the simulation plus just enough of the
+// model to put the player back where they were.
+type saveFile struct {
+	Version    int            `json:"version"`
+	Scene      GameScene      `json:"scene"`
+	Ticks      int            `json:"ticks"`
+	Difficulty sim.Difficulty `json:"difficulty"`
+	State      sim.State      `json:"state"`
+}
+
+// savePath is where the save lives, under the user's config dir.
+func savePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "theStartupTM", "save.json"), nil
+}
+
+func saveExists() bool {
+	path, err := savePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func (m model) snapshot() saveFile {
+	return saveFile{
+		Version:    SAVE_VERSION,
+		Scene:      m.scene,
+		Ticks:      m.ticks,
+		Difficulty: m.difficulty,
+		State:      m.State,
+	}
+}
+
+// writeSave writes s to path, going through a temp file so a crash halfway
+// through never leaves a truncated save behind.
+func writeSave(path string, s saveFile) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func readSave(path string) (saveFile, error) {
+	var s saveFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if s.Version != SAVE_VERSION {
+		return s, fmt.Errorf("%s is save version %d, want %d", path, s.Version, SAVE_VERSION)
+	}
+	return s, nil
+}
+
+// loadGame reads the save from disk into the model.
+func loadGame() (saveFile, error) {
+	path, err := savePath()
+	if err != nil {
+		return saveFile{}, err
+	}
+	s, err := readSave(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, errors.New("no saved game")
+	}
+	return s, err
+}
+
+// withSave puts the model back into the saved run. A run saved from the
+// pause menu comes back paused, and one saved mid-event comes back to it.
+func (m model) withSave(s saveFile) model {
+	m.State = s.State
+	m.rng = rand.New(rand.NewSource(s.State.Seed))
+	m.ticks = s.Ticks
+	m.difficulty = s.Difficulty
+	m.balance = m.difficulty.Apply(m.baseBalance)
+	m.scene = s.Scene
+	m.gameTicking = true
+	if m.scene == Start {
+		m.scene = Game
+	}
+	if m.scene == Paused {
+		m = m.pause()
+	}
+	if m.scene == Game && m.PendingEvent != "" {
+		m = m.showEvent()
+	}
+	return m
+}
+
+// save writes the run to disk in the background. Replays never save, so
+// watching one can't clobber the player's own game.
+func (m model) save() tea.Cmd {
+	if m.replaying {
+		return nil
+	}
+	return saveCmd(m.snapshot())
+}
+
+type savedMsg struct {
+	err error
+}
+
+// saveCmd writes s in the background, reporting back with a savedMsg.
+func saveCmd(s saveFile) tea.Cmd {
+	return func() tea.Msg {
+		path, err := savePath()
+		if err == nil {
+			err = writeSave(path, s)
+		}
+		return savedMsg{err}
+	}
+}
+
+// discardSave deletes the save in the background once its run is over, so
+// the start screen doesn't offer to load a dead run. Like save, it leaves the
+// player's own game alone during a replay.
+func (m model) discardSave() tea.Cmd {
+	if m.replaying {
+		return nil
+	}
+	return func() tea.Msg {
+		path, err := savePath()
+		if err == nil {
+			err = os.Remove(path)
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return saveDiscardedMsg{err}
+	}
+}
+
+type saveDiscardedMsg struct {
+	err error
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"theStartupTM/sim"
+)
+
+func TestSaveRoundTrip(t *testing.T) {
+	m := initialModel(sim.DefaultBalance(), 7)
+	m.difficulty = sim.Hard
+	m = m.startGame()
+	m.Cash = 500
+	m = m.hire(sim.RoleDev)
+	for i := 0; i < 20 && m.scene == Game; i++ {
+		m = onGameTick(m)
+	}
+
+	path := filepath.Join(t.TempDir(), "save.json")
+	if err := writeSave(path, m.snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	s, err := readSave(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != m.snapshot() {
+		t.Fatalf("read back\n%+v\nwant\n%+v", s, m.snapshot())
+	}
+
+	loaded := initialModel(sim.DefaultBalance(), 1).withSave(s)
+	if loaded.State != m.State {
+		t.Errorf("loaded State =\n%+v\nwant\n%+v", loaded.State, m.State)
+	}
+	if loaded.ticks != m.ticks || loaded.difficulty != m.difficulty || loaded.scene != m.scene {
+		t.Errorf("loaded ticks %d, %v, scene %v; want %d, %v, scene %v",
+			loaded.ticks, loaded.difficulty, loaded.scene, m.ticks, m.difficulty, m.scene)
+	}
+	if !reflect.DeepEqual(loaded.balance, m.balance) {
+		t.Errorf("loaded balance isn't the %v balance the run was saved under", m.difficulty)
+	}
+}
+
+func TestWithSaveScene(t *testing.T) {
+	tests := []struct {
+		name  string
+		save  saveFile
+		scene GameScene
+	}{
+		{"mid-game", saveFile{Scene: Game}, Game},
+		{"from the pause menu", saveFile{Scene: Paused}, Paused},
+		{"before the first tick", saveFile{Scene: Start}, Game},
+		{"mid-event", saveFile{Scene: Game, State: sim.State{PendingEvent: "Viral tweet"}}, Choosing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := initialModel(sim.DefaultBalance(), 1).withSave(tt.save)
+			if m.scene != tt.scene {
+				t.Errorf("scene = %v, want %v", m.scene, tt.scene)
+			}
+		})
+	}
+}
+
+func TestReadSaveRejects(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"missing", filepath.Join(dir, "nope.json")},
+		{"not json", write("garbage.json", "{")},
+		{"an older version", write("old.json", `{"version": 1}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readSave(tt.path); err == nil {
+				t.Errorf("readSave succeeded")
+			}
+		})
+	}
+}
+
+func TestDiscardSave(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := initialModel(sim.DefaultBalance(), 1).startGame()
+
+	if msg := m.save()().(savedMsg); msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	if !saveExists() {
+		t.Fatal("no save after saving")
+	}
+	// Discarding twice is fine: the second time there's nothing to delete.
+	for i := 0; i < 2; i++ {
+		if msg := m.discardSave()().(saveDiscardedMsg); msg.err != nil {
+			t.Fatal(msg.err)
+		}
+	}
+	if saveExists() {
+		t.Error("the save is still there")
+	}
+
+	m.replaying = true
+	if m.save() != nil || m.discardSave() != nil {
+		t.Error("a replay touched the player's save")
+	}
+}
+
+func TestLoadGameWithoutSave(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, err := loadGame()
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		t.Errorf("err = %v, want a friendly no saved game", err)
+	}
+}
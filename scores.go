@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"theStartupTM/sim"
+)
+
+// SCORES_VERSION is bumped whenever the leaderboard file changes shape
+// incompatibly.
+const SCORES_VERSION = 1
+
+// MAX_SCORES is how many runs the leaderboard remembers.
+var MAX_SCORES = 10
+
+// score is one finished run on the leaderboard. Runs rank by how long they
+// survived, then by peak company value.
+type score struct {
+	Initials   string         `json:"initials"`
+	Survived   float64        `json:"survived"` // seconds of game time
+	PeakValue  int            `json:"peak_value"`
+	Seed       int64          `json:"seed"`
+	Difficulty sim.Difficulty `json:"difficulty"`
+	When       time.Time      `json:"when"`
+}
+
+type scoresFile struct {
+	Version int     `json:"version"`
+	Scores  []score `json:"scores"`
+}
+
+func scoresPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "theStartupTM", "scores.json"), nil
+}
+
+// loadScores reads the leaderboard. A missing or unreadable file is an
+// empty leaderboard; it's not worth stopping the game over.
+func loadScores() []score {
+	path, err := scoresPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var f scoresFile
+	if json.Unmarshal(data, &f) != nil || f.Version != SCORES_VERSION {
+		return nil
+	}
+	return f.Scores
+}
+
+type scoresSavedMsg struct {
+	err error
+}
+
+// saveScoresCmd writes the leaderboard in the background.
+func saveScoresCmd(scores []score) tea.Cmd {
+	return func() tea.Msg {
+		path, err := scoresPath()
+		if err != nil {
+			return scoresSavedMsg{err}
+		}
+		data, err := json.MarshalIndent(scoresFile{Version: SCORES_VERSION, Scores: scores}, "", "  ")
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0o755)
+		}
+		if err == nil {
+			err = os.WriteFile(path+".tmp", data, 0o644)
+		}
+		if err == nil {
+			err = os.Rename(path+".tmp", path)
+		}
+		return scoresSavedMsg{err}
+	}
+}
+
+func (a score) beats(b score) bool {
+	if a.Survived != b.Survived {
+		return a.Survived > b.Survived
+	}
+	return a.PeakValue > b.PeakValue
+}
+
+// ranked adds s to scores, keeping the best MAX_SCORES. It reports where s
+// landed, or -1 if it didn't make the cut.
+func ranked(scores []score, s score) ([]score, int) {
+	scores = append(append([]score(nil), scores...), s)
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].beats(scores[j]) })
+	rank := -1
+	for i := range scores {
+		if scores[i] == s {
+			rank = i
+			break
+		}
+	}
+	if len(scores) > MAX_SCORES {
+		scores = scores[:MAX_SCORES]
+	}
+	if rank >= MAX_SCORES {
+		rank = -1
+	}
+	return scores, rank
+}
+
+// runScore is the current run as a leaderboard entry.
+func (m model) runScore() score {
+	return score{
+		Survived:   m.Stats.Elapsed,
+		PeakValue:  m.Stats.PeakPricePerShare,
+		Seed:       m.Seed,
+		Difficulty: m.difficulty,
+	}
+}
+
+func (m model) qualifies() bool {
+	_, rank := ranked(m.scores, m.runScore())
+	return rank >= 0
+}
+
+func newInitialsInput() textinput.Model {
+	t := textinput.New()
+	t.Placeholder = "AAA"
+	t.CharLimit = 3
+	t.Width = 3
+	t.Prompt = "Your initials: "
+	return t
+}
+
+// askInitials is called as a run ends: a run good enough for the leaderboard
+// gets to sign it. Replays never do.
+func (m model) askInitials() model {
+	if m.replaying || !m.qualifies() {
+		return m
+	}
+	m.enteringInitials = true
+	m.initials = newInitialsInput()
+	m.initials.Focus()
+	return m
+}
+
+var initialsKeys = struct {
+	Submit key.Binding
+	Skip   key.Binding
+}{
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "sign the leaderboard"),
+	),
+	Skip: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "skip"),
+	),
+}
+
+func (m model) updateInitials(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, initialsKeys.Submit):
+		initials := strings.ToUpper(strings.TrimSpace(m.initials.Value()))
+		if initials == "" {
+			return m, nil
+		}
+		s := m.runScore()
+		s.Initials = initials
+		s.When = time.Now()
+		m.scores, m.scoreRank = ranked(m.scores, s)
+		m.enteringInitials = false
+		return m, saveScoresCmd(m.scores)
+
+	case key.Matches(msg, initialsKeys.Skip):
+		m.enteringInitials = false
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.initials, cmd = m.initials.Update(msg)
+	return m, cmd
+}
+
+var scoreTitleStyle = lipgloss.NewStyle().Bold(true)
+var scoreHighlightStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("35")).Bold(true)
+
+// ScoresView lists the top n runs, highlighting the one at highlight.
+func ScoresView(scores []score, n, highlight int) string {
+	lines := []string{scoreTitleStyle.Render("HIGH SCORES")}
+	if len(scores) == 0 {
+		lines = append(lines, "no runs yet")
+	}
+	for i, s := range scores {
+		if i == n {
+			break
+		}
+		survived := time.Duration(s.Survived * float64(time.Second)).Round(time.Second)
+		line := fmt.Sprintf("%2d. %-3s %8s %9d  %s", i+1, s.Initials, survived, s.PeakValue, s.Difficulty)
+		if i == highlight {
+			line = scoreHighlightStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func TestRanked(t *testing.T) {
+	// full is a leaderboard of MAX_SCORES runs that each survived 100s, with
+	// peak values 10, 9, ... 1.
+	full := make([]score, MAX_SCORES)
+	for i := range full {
+		full[i] = score{Initials: "AAA", Survived: 100, PeakValue: MAX_SCORES - i}
+	}
+
+	tests := []struct {
+		name     string
+		scores   []score
+		s        score
+		wantRank int
+		wantLen  int
+	}{
+		{
+			name:     "first score",
+			s:        score{Initials: "NEW", Survived: 1},
+			wantRank: 0,
+			wantLen:  1,
+		},
+		{
+			name:     "outlasting everyone",
+			scores:   full,
+			s:        score{Initials: "NEW", Survived: 101},
+			wantRank: 0,
+			wantLen:  MAX_SCORES,
+		},
+		{
+			name:   "a tie on time goes to the higher peak",
+			scores: full,
+			s:      score{Initials: "NEW", Survived: 100, PeakValue: 8},
+			// Behind the peaks of 10, 9 and the 8 that got there first.
+			wantRank: 3,
+			wantLen:  MAX_SCORES,
+		},
+		{
+			name:     "a full tie ranks behind the score already there",
+			scores:   full[:3],
+			s:        score{Initials: "NEW", Survived: 100, PeakValue: 9},
+			wantRank: 2,
+			wantLen:  4,
+		},
+		{
+			name:     "not making the cut",
+			scores:   full,
+			s:        score{Initials: "NEW", Survived: 99},
+			wantRank: -1,
+			wantLen:  MAX_SCORES,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := append([]score(nil), tt.scores...)
+			got, rank := ranked(tt.scores, tt.s)
+			if rank != tt.wantRank {
+				t.Errorf("rank = %d, want %d", rank, tt.wantRank)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("len = %d, want %d", len(got), tt.wantLen)
+			}
+			if rank >= 0 && got[rank] != tt.s {
+				t.Errorf("scores[%d] = %+v, want %+v", rank, got[rank], tt.s)
+			}
+			for i := 1; i < len(got); i++ {
+				if got[i].beats(got[i-1]) {
+					t.Errorf("scores[%d] %+v beats scores[%d] %+v", i, got[i], i-1, got[i-1])
+				}
+			}
+			for i := range before {
+				if tt.scores[i] != before[i] {
+					t.Fatalf("ranked changed the leaderboard it was given")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"theStartupTM/sim"
+)
+
+type shopKeyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Buy   key.Binding
+	Close key.Binding
+}
+
+func (k shopKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Buy, k.Close}
+}
+
+func (k shopKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var shopKeys = shopKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Buy: key.NewBinding(
+		key.WithKeys("enter", " "),
+		key.WithHelp("enter", "buy"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "$"),
+		key.WithHelp("esc", "close"),
+	),
+}
+
+// The game keeps running while the shop is open; it only takes over the keys.
+func (m model) updateShop(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, shopKeys.Up):
+		m.shopCursor = (m.shopCursor + len(sim.Upgrades) - 1) % len(sim.Upgrades)
+
+	case key.Matches(msg, shopKeys.Down):
+		m.shopCursor = (m.shopCursor + 1) % len(sim.Upgrades)
+
+	case key.Matches(msg, shopKeys.Buy):
+		m = m.buy(sim.Upgrades[m.shopCursor])
+
+	case key.Matches(msg, shopKeys.Close):
+		m.shopOpen = false
+	}
+	return m, nil
+}
+
+// buy tries to pay for one u, leaving a notice on screen either way.
+func (m model) buy(u sim.Upgrade) model {
+	s, err := sim.Buy(m.balance, m.State, u)
+	if err != nil {
+		m.notice = "Can't buy: " + err.Error()
+		m.noticeFrames = NOTICE_FRAMES
+		return m
+	}
+	m.State = s
+	m.notice = fmt.Sprintf("Bought %s #%d", upgradeLabels[u], m.Owned(u))
+	m.noticeFrames = NOTICE_FRAMES
+	return m
+}
+
+var upgradeLabels = map[sim.Upgrade]string{
+	sim.UpgradeOffice:      "Office",
+	sim.UpgradeServers:     "Servers",
+	sim.UpgradePerks:       "Perks",
+	sim.UpgradeAcquisition: "Acquisition",
+}
+
+// upgradeEffect describes what one more u does under the current balance.
+func (m model) upgradeEffect(u sim.Upgrade) string {
+	b := m.balance
+	switch u {
+	case sim.UpgradeOffice:
+		return fmt.Sprintf("+%d market, +%d value", b.MarketPerOffice, b.PricePerOffice)
+	case sim.UpgradeServers:
+		return fmt.Sprintf("bugs drive away %.0f%% fewer users", b.ChurnCutPerServer*100)
+	case sim.UpgradePerks:
+		return fmt.Sprintf("+%.0f%% dev output", b.ProductivityPerPerk*100)
+	case sim.UpgradeAcquisition:
+		return fmt.Sprintf("+%d features, +%d users, +%d bugs", b.AcquisitionFeatures, b.AcquisitionUsers, b.AcquisitionBugs)
+	}
+	return ""
+}
+
+func (m model) ShopView() string {
+	cols := []table.Column{
+		{Title: "Upgrade", Width: 12},
+		{Title: "Owned", Width: 6},
+		{Title: "Cost", Width: 10},
+		{Title: "Effect", Width: 34},
+	}
+	rows := make([]table.Row, len(sim.Upgrades))
+	for i, u := range sim.Upgrades {
+		rows[i] = table.Row{
+			upgradeLabels[u],
+			fmt.Sprintf("%d", m.Owned(u)),
+			fmt.Sprintf("$%d", m.balance.UpgradeCost(m.State, u)),
+			m.upgradeEffect(u),
+		}
+	}
+	t := table.New(
+		table.WithColumns(cols),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(len(rows)),
+	)
+	t.SetCursor(m.shopCursor)
+	shop := "SHOP  " + cashStyle.Render(fmt.Sprintf("$%d to spend", m.Cash)) + "\n\n" +
+		t.View() + "\n\n" + m.helpModel.ShortHelpView(shopKeys.ShortHelp())
+	return devBorder.Render(shop)
+}
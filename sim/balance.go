@@ -0,0 +1,197 @@
+package sim
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Balance is every tuning number in the game. The shipped values live in
+// balance.json; designers can copy it, tweak it and pass it with --balance.
+type Balance struct {
+	UsersPerSecondPerFeature       float64 `json:"users_per_second_per_feature"`
+	UsersPerSecondPerMarketer      float64 `json:"users_per_second_per_marketer"`
+	UsersPerSecondPerBug           float64 `json:"users_per_second_per_bug"`
+	CashPerSecondPerUserPerFeature int     `json:"cash_per_second_per_user_per_feature"`
+	BugsPerSecondPerFeature        float64 `json:"bugs_per_second_per_feature"`
+	BugsPerSecondPerDev            float64 `json:"bugs_per_second_per_dev"`
+	BugsFixedPerSecondPerQA        float64 `json:"bugs_fixed_per_second_per_qa"`
+	BugsFixedPerSecondPerDev       float64 `json:"bugs_fixed_per_second_per_dev"`
+	FeaturesPerSecondPerDev        float64 `json:"features_per_second_per_dev"`
+	FeatureDragPerQA               float64 `json:"feature_drag_per_qa"`
+	DevSalaryPerSecond             int     `json:"dev_salary_per_second"`
+	QASalaryPerSecond              int     `json:"qa_salary_per_second"`
+	MarketerSalaryPerSecond        int     `json:"marketer_salary_per_second"`
+	StrategistSalaryPerSecond      int     `json:"strategist_salary_per_second"`
+	InfluencerSalaryPerSecond      int     `json:"influencer_salary_per_second"`
+	DevSigningCost                 int     `json:"dev_signing_cost"`
+	QASigningCost                  int     `json:"qa_signing_cost"`
+	MarketerSigningCost            int     `json:"marketer_signing_cost"`
+	StrategistSigningCost          int     `json:"strategist_signing_cost"`
+	InfluencerSigningCost          int     `json:"influencer_signing_cost"`
+	PricePerFeature                int     `json:"price_per_feature"`
+	PricePerUser                   int     `json:"price_per_user"`
+	PricePerBug                    int     `json:"price_per_bug"`
+	PricePerDev                    int     `json:"price_per_dev"`
+	PricePerMarketer               int     `json:"price_per_marketer"`
+	PricePerStrategist             int     `json:"price_per_strategist"`
+	StartingMarket                 int     `json:"starting_market"`
+	MarketPerSecondPerStrategist   float64 `json:"market_per_second_per_strategist"`
+	PricePerInfluencer             int     `json:"price_per_influencer"`
+	// An influencer post goes viral at random. It brings in a share of the
+	// users not yet won over, unless there are at least BacklashBugs bugs, in
+	// which case the crowd sees the bugs and a share of existing users leave.
+	ViralChancePerSecondPerInfluencer float64 `json:"viral_chance_per_second_per_influencer"`
+	ViralShareOfMarket                float64 `json:"viral_share_of_market"`
+	BacklashBugs                      int     `json:"backlash_bugs"`
+	BacklashShareOfUsers              float64 `json:"backlash_share_of_users"`
+	// The share price is pulled toward the fundamental value by
+	// PricePullPerSecond per unit of gap while PriceDampingPerSecond bleeds
+	// off its momentum. PriceVolatility is the size of its random walk, as a
+	// share of the price (or PriceNoiseFloor, if that's bigger). News moves
+	// it by NewsShockShare at a time, up or down.
+	PricePullPerSecond    float64 `json:"price_pull_per_second"`
+	PriceDampingPerSecond float64 `json:"price_damping_per_second"`
+	PriceVolatility       float64 `json:"price_volatility"`
+	PriceNoiseFloor       float64 `json:"price_noise_floor"`
+	NewsChancePerSecond   float64 `json:"news_chance_per_second"`
+	NewsShockShare        float64 `json:"news_shock_share"`
+	CashCap               int     `json:"cash_cap"`
+	// The company collapses once the price has been under CollapsePrice for
+	// CollapseSeconds straight. The clock only starts CollapseGraceSeconds
+	// into the run, as a new company trades right around its opening price.
+	CollapsePrice        int     `json:"collapse_price"`
+	CollapseSeconds      float64 `json:"collapse_seconds"`
+	CollapseGraceSeconds float64 `json:"collapse_grace_seconds"`
+	// Rounds are the funding rounds investors offer, in order. Every missed
+	// growth target claws BoardClawback of the founder's stake back and
+	// knocks BoardPriceHit off the share price; once the founder owns less
+	// than OustedBelowOwnership, the board votes them out.
+	Rounds               []Round `json:"rounds"`
+	BoardClawback        float64 `json:"board_clawback"`
+	BoardPriceHit        float64 `json:"board_price_hit"`
+	OustedBelowOwnership float64 `json:"ousted_below_ownership"`
+	// Each upgrade costs its base price to begin with, UpgradeCostGrowth
+	// times more with every one already bought. Offices add to the market
+	// and to the company's value; servers cut the users lost to bugs by
+	// ChurnCutPerServer; perks make devs ProductivityPerPerk more productive;
+	// an acquisition brings in a rival's features, users and bugs.
+	OfficeCost          int     `json:"office_cost"`
+	ServersCost         int     `json:"servers_cost"`
+	PerksCost           int     `json:"perks_cost"`
+	AcquisitionCost     int     `json:"acquisition_cost"`
+	UpgradeCostGrowth   float64 `json:"upgrade_cost_growth"`
+	MarketPerOffice     int     `json:"market_per_office"`
+	PricePerOffice      int     `json:"price_per_office"`
+	ChurnCutPerServer   float64 `json:"churn_cut_per_server"`
+	ProductivityPerPerk float64 `json:"productivity_per_perk"`
+	AcquisitionFeatures int     `json:"acquisition_features"`
+	AcquisitionUsers    int     `json:"acquisition_users"`
+	AcquisitionBugs     int     `json:"acquisition_bugs"`
+	// Tech debt builds up by TechDebtPerFeatureByHand for every feature the
+	// founder hacks out, and by TechDebtPerSecondPerRushedDev per dev for
+	// every bit of dev focus on features past SustainableFeatureShare. Each
+	// point of it makes features grow bugs BugsPerTechDebt faster. A refactor
+	// sprint lasts RefactorSeconds and pays RefactorPerSecondPerDev back.
+	TechDebtPerFeatureByHand      float64 `json:"tech_debt_per_feature_by_hand"`
+	TechDebtPerSecondPerRushedDev float64 `json:"tech_debt_per_second_per_rushed_dev"`
+	SustainableFeatureShare       float64 `json:"sustainable_feature_share"`
+	BugsPerTechDebt               float64 `json:"bugs_per_tech_debt"`
+	RefactorSeconds               float64 `json:"refactor_seconds"`
+	RefactorPerSecondPerDev       float64 `json:"refactor_per_second_per_dev"`
+	// Events is the deck of things that can happen to the company; one is
+	// dealt at random with EventChancePerSecond.
+	Events               []Event `json:"events"`
+	EventChancePerSecond float64 `json:"event_chance_per_second"`
+}
+
+//go:embed balance.json
+var defaultBalance []byte
+
+// DefaultBalance returns the numbers the game ships with.
+func DefaultBalance() Balance {
+	b, err := decodeBalance(Balance{}, defaultBalance)
+	if err != nil {
+		panic("sim: embedded balance.json is broken: " + err.Error())
+	}
+	return b
+}
+
+// LoadBalance reads a balance file. Anything the file leaves out keeps its
+// default value, so a file only needs the numbers being experimented with.
+func LoadBalance(path string) (Balance, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Balance{}, err
+	}
+	b, err := decodeBalance(DefaultBalance(), data)
+	if err == nil {
+		err = b.Validate()
+	}
+	if err != nil {
+		return Balance{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Validate rejects numbers the game can't run on, so a slip in a balance
+// file is caught at load rather than as a crash mid-run.
+func (b Balance) Validate() error {
+	if b.CashCap <= 1 {
+		return fmt.Errorf("cash_cap is %d, must be more than 1", b.CashCap)
+	}
+	if b.CollapseSeconds < 0 || b.CollapseGraceSeconds < 0 {
+		return fmt.Errorf("collapse_seconds and collapse_grace_seconds are %v and %v, must not be negative",
+			b.CollapseSeconds, b.CollapseGraceSeconds)
+	}
+	if b.UpgradeCostGrowth < 1 {
+		return fmt.Errorf("upgrade_cost_growth is %v, must be at least 1", b.UpgradeCostGrowth)
+	}
+	chances := []struct {
+		name   string
+		chance float64
+	}{
+		{"viral_chance_per_second_per_influencer", b.ViralChancePerSecondPerInfluencer},
+		{"news_chance_per_second", b.NewsChancePerSecond},
+		{"event_chance_per_second", b.EventChancePerSecond},
+	}
+	for _, c := range chances {
+		if c.chance < 0 {
+			return fmt.Errorf("%s is %v, must not be negative", c.name, c.chance)
+		}
+	}
+	// The game waits on the player's choice, so a company with no cash
+	// must still have one it can take.
+	for _, e := range b.Events {
+		if !slices.ContainsFunc(e.Choices, func(c Choice) bool { return c.Effect.Cash >= 0 }) {
+			return fmt.Errorf("event %q has no choice that doesn't cost cash", e.Name)
+		}
+	}
+	return nil
+}
+
+// decodeBalance overlays data onto b, rejecting keys we don't know so a typo
+// doesn't silently fall back to the default.
+func decodeBalance(b Balance, data []byte) (Balance, error) {
+	// A list in the file replaces the default list outright. Left alone, the
+	// decoder would merge it element by element into the defaults, so a
+	// one-event deck would inherit the first default event's choices.
+	var keys map[string]json.RawMessage
+	if json.Unmarshal(data, &keys) == nil {
+		if _, ok := keys["rounds"]; ok {
+			b.Rounds = nil
+		}
+		if _, ok := keys["events"]; ok {
+			b.Events = nil
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&b); err != nil {
+		return Balance{}, err
+	}
+	return b, nil
+}
@@ -0,0 +1,116 @@
+package sim
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultBalanceIsValid(t *testing.T) {
+	if err := DefaultBalance().Validate(); err != nil {
+		t.Errorf("the shipped balance.json doesn't validate: %v", err)
+	}
+}
+
+func TestLoadBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr bool
+		want    func(b Balance) Balance
+	}{
+		{
+			name: "a partial file keeps the other defaults",
+			file: `{"cash_cap": 5000, "dev_salary_per_second": 3}`,
+			want: func(b Balance) Balance {
+				b.CashCap = 5000
+				b.DevSalaryPerSecond = 3
+				return b
+			},
+		},
+		{
+			name: "a list replaces the default list",
+			file: `{"events": [{"name": "Only", "text": "The one card.", "choices": [{"label": "Fine"}]}]}`,
+			want: func(b Balance) Balance {
+				b.Events = []Event{{Name: "Only", Text: "The one card.", Choices: []Choice{{Label: "Fine"}}}}
+				return b
+			},
+		},
+		{
+			name: "an empty list empties the deck",
+			file: `{"events": [], "rounds": []}`,
+			want: func(b Balance) Balance {
+				b.Events = []Event{}
+				b.Rounds = []Round{}
+				return b
+			},
+		},
+		{
+			name:    "an unknown key",
+			file:    `{"cash_capp": 5000}`,
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			file:    `{"cash_cap": `,
+			wantErr: true,
+		},
+		{
+			name:    "numbers the game can't run on",
+			file:    `{"cash_cap": 0}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "balance.json")
+			if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			b, err := LoadBalance(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if want := tt.want(DefaultBalance()); !reflect.DeepEqual(b, want) {
+				t.Errorf("LoadBalance =\n%+v\nwant\n%+v", b, want)
+			}
+		})
+	}
+
+	if _, err := LoadBalance(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("loading a missing file succeeded")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		spoil func(b *Balance)
+	}{
+		{"no cash cap", func(b *Balance) { b.CashCap = 0 }},
+		{"a cash cap of one", func(b *Balance) { b.CashCap = 1 }},
+		{"a negative crash window", func(b *Balance) { b.CollapseSeconds = -1 }},
+		{"a negative grace period", func(b *Balance) { b.CollapseGraceSeconds = -1 }},
+		{"upgrades that get cheaper", func(b *Balance) { b.UpgradeCostGrowth = 0.9 }},
+		{"a negative viral chance", func(b *Balance) { b.ViralChancePerSecondPerInfluencer = -0.1 }},
+		{"a negative news chance", func(b *Balance) { b.NewsChancePerSecond = -0.1 }},
+		{"a negative event chance", func(b *Balance) { b.EventChancePerSecond = -0.1 }},
+		{"an event with no choices", func(b *Balance) { b.Events = []Event{{Name: "Stuck"}} }},
+		{"an event only money gets out of", func(b *Balance) {
+			b.Events = []Event{{Name: "Stuck", Choices: []Choice{{Label: "Pay", Effect: Effect{Cash: -1}}}}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := DefaultBalance()
+			tt.spoil(&b)
+			if err := b.Validate(); err == nil {
+				t.Errorf("Validate accepted it")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package sim
+
+import (
+	"errors"
+	"math"
+)
+
+// MAX_TECH_DEBT is as bad as the codebase gets.
+const MAX_TECH_DEBT = 100
+
+var (
+	// ErrRefactoring is returned when a refactor sprint is already underway.
+	ErrRefactoring = errors.New("already refactoring")
+	// ErrNoTechDebt is returned when there's no tech debt to pay down.
+	ErrNoTechDebt = errors.New("no tech debt to pay down")
+)
+
+// debtBugMultiplier is how much faster features grow bugs under the current
+// tech debt.
+func (b Balance) debtBugMultiplier(s State) float64 {
+	return 1 + b.BugsPerTechDebt*float64(s.CopyPasteModifier)
+}
+
+// StartRefactor stops all dev work for a sprint spent paying down tech debt.
+func StartRefactor(b Balance, s State) (State, error) {
+	if s.RefactorSeconds > 0 {
+		return s, ErrRefactoring
+	}
+	if s.CopyPasteModifier == 0 {
+		return s, ErrNoTechDebt
+	}
+	s.RefactorSeconds = b.RefactorSeconds
+	return s, nil
+}
+
+// accrue moves the tech debt meter: up while devs are rushed onto features,
+// down during a refactor sprint, where the founder pitches in as one more
+// dev.
+func accrue(b Balance, s State, featureShare, dt float64) State {
+	if s.RefactorSeconds > 0 {
+		s.TechDebtPerSecond = -float64(s.Devs+1) * b.RefactorPerSecondPerDev
+		s.RefactorSeconds = math.Max(0, s.RefactorSeconds-dt)
+	} else {
+		s.TechDebtPerSecond = float64(s.Devs) * b.TechDebtPerSecondPerRushedDev *
+			math.Max(0, featureShare-b.SustainableFeatureShare)
+	}
+	s.ProgressTowardDebt += s.TechDebtPerSecond * dt
+	s.CopyPasteModifier = max(0, min(MAX_TECH_DEBT, s.CopyPasteModifier+drain(&s.ProgressTowardDebt)))
+	// A clean codebase ends the sprint early and owes nothing.
+	if s.CopyPasteModifier == 0 {
+		s.RefactorSeconds = 0
+		s.ProgressTowardDebt = math.Max(0, s.ProgressTowardDebt)
+	}
+	return s
+}
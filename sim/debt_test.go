@@ -0,0 +1,86 @@
+package sim
+
+import "testing"
+
+func TestAccrue(t *testing.T) {
+	b := DefaultBalance()
+	tests := []struct {
+		name         string
+		s            State
+		featureShare float64
+		wantDebt     int
+		wantRefactor float64
+	}{
+		{
+			name:         "sustainable pace owes nothing",
+			s:            State{Devs: 10},
+			featureShare: b.SustainableFeatureShare,
+			wantDebt:     0,
+		},
+		{
+			name:         "rushed devs run up debt",
+			s:            State{Devs: 10},
+			featureShare: 1,
+			// 10 devs × 0.5 × (1 - 0.6) over share.
+			wantDebt: 2,
+		},
+		{
+			name:         "debt tops out",
+			s:            State{Devs: 10, CopyPasteModifier: MAX_TECH_DEBT - 1},
+			featureShare: 1,
+			wantDebt:     MAX_TECH_DEBT,
+		},
+		{
+			name:         "refactoring pays it down, founder included",
+			s:            State{Devs: 4, CopyPasteModifier: 10, RefactorSeconds: 5},
+			featureShare: 1,
+			wantDebt:     5,
+			wantRefactor: 4,
+		},
+		{
+			name:         "a clean codebase ends the sprint early",
+			s:            State{Devs: 4, CopyPasteModifier: 3, RefactorSeconds: 5},
+			featureShare: 1,
+			wantDebt:     0,
+			wantRefactor: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := accrue(b, tt.s, tt.featureShare, 1)
+			if s.CopyPasteModifier != tt.wantDebt {
+				t.Errorf("CopyPasteModifier = %d, want %d", s.CopyPasteModifier, tt.wantDebt)
+			}
+			if s.RefactorSeconds != tt.wantRefactor {
+				t.Errorf("RefactorSeconds = %v, want %v", s.RefactorSeconds, tt.wantRefactor)
+			}
+			if s.ProgressTowardDebt < 0 || s.ProgressTowardDebt >= 1 {
+				t.Errorf("ProgressTowardDebt = %v, want a fraction in [0, 1)", s.ProgressTowardDebt)
+			}
+		})
+	}
+}
+
+func TestStartRefactor(t *testing.T) {
+	b := DefaultBalance()
+	tests := []struct {
+		name    string
+		s       State
+		wantErr error
+	}{
+		{"no debt", State{}, ErrNoTechDebt},
+		{"already refactoring", State{CopyPasteModifier: 5, RefactorSeconds: 1}, ErrRefactoring},
+		{"starts a sprint", State{CopyPasteModifier: 5}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := StartRefactor(b, tt.s)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && s.RefactorSeconds != b.RefactorSeconds {
+				t.Errorf("RefactorSeconds = %v, want %v", s.RefactorSeconds, b.RefactorSeconds)
+			}
+		})
+	}
+}
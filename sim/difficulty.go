@@ -0,0 +1,65 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Difficulty is a preset that scales a Balance before a run starts.
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Normal
+	Hard
+	Nightmare
+)
+
+// Difficulties lists every preset, easiest first.
+var Difficulties = []Difficulty{Easy, Normal, Hard, Nightmare}
+
+type difficultyPreset struct {
+	name string
+	// Multipliers on the matching Balance fields.
+	cashCap float64
+	bugRate float64
+	churn   float64
+	// Added to Balance.CollapsePrice; the company must stay above it.
+	collapseOffset int
+}
+
+var difficultyPresets = map[Difficulty]difficultyPreset{
+	Easy:      {"Easy", 2, 0.5, 0.5, -10000},
+	Normal:    {"Normal", 1, 1, 1, 0},
+	Hard:      {"Hard", 0.5, 1.5, 1.5, 50},
+	Nightmare: {"Nightmare", 0.25, 2, 2, 100},
+}
+
+func (d Difficulty) String() string {
+	return difficultyPresets[d].name
+}
+
+// Apply returns b scaled for d.
+func (d Difficulty) Apply(b Balance) Balance {
+	p, ok := difficultyPresets[d]
+	if !ok {
+		return b
+	}
+	b.CashCap = int(math.Round(float64(b.CashCap) * p.cashCap))
+	b.BugsPerSecondPerFeature *= p.bugRate
+	b.BugsPerSecondPerDev *= p.bugRate
+	b.UsersPerSecondPerBug *= p.churn
+	b.CollapsePrice += p.collapseOffset
+	return b
+}
+
+// ParseDifficulty looks a preset up by name, ignoring case.
+func ParseDifficulty(name string) (Difficulty, error) {
+	for _, d := range Difficulties {
+		if strings.EqualFold(d.String(), name) {
+			return d, nil
+		}
+	}
+	return Normal, fmt.Errorf("unknown difficulty %q", name)
+}
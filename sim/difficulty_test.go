@@ -0,0 +1,65 @@
+package sim
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDifficultyApply(t *testing.T) {
+	b := DefaultBalance()
+	b.CollapsePrice = 10
+	tests := []struct {
+		d             Difficulty
+		cashCap       int
+		bugRate       float64
+		churn         float64
+		collapsePrice int
+	}{
+		{Easy, 2 * b.CashCap, 0.5, 0.5, 10 - 10000},
+		{Normal, b.CashCap, 1, 1, 10},
+		{Hard, b.CashCap / 2, 1.5, 1.5, 60},
+		{Nightmare, b.CashCap / 4, 2, 2, 110},
+	}
+	for _, tt := range tests {
+		t.Run(tt.d.String(), func(t *testing.T) {
+			got := tt.d.Apply(b)
+			want := b
+			want.CashCap = tt.cashCap
+			want.BugsPerSecondPerFeature *= tt.bugRate
+			want.BugsPerSecondPerDev *= tt.bugRate
+			want.UsersPerSecondPerBug *= tt.churn
+			want.CollapsePrice = tt.collapsePrice
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Apply =\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+
+	if got := Difficulty(99).Apply(b); !reflect.DeepEqual(got, b) {
+		t.Errorf("an unknown difficulty changed the balance")
+	}
+}
+
+func TestParseDifficulty(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Difficulty
+		wantErr bool
+	}{
+		{"easy", Easy, false},
+		{"NIGHTMARE", Nightmare, false},
+		{"Hard", Hard, false},
+		{"impossible", Normal, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDifficulty(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDifficulty = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package sim
+
+// Ending is how a run finished.
+type Ending int
+
+const (
+	Running Ending = iota
+	Crushed
+	Collapsed
+	Bankrupt
+	Ousted
+)
+
+var endingCauses = map[Ending]string{
+	Crushed:   "You've been crushed under the weight of your own success...\nA tragedy has befallen all mankind.",
+	Collapsed: "Your enterprise has colapsed around you. A flash in the pan, nothing more.",
+	Bankrupt:  "The money ran out before the ideas did. Payroll bounced, and so did everyone else.",
+	Ousted:    "The board has lost confidence in your leadership. Security will see you out.",
+}
+
+// end finishes the run with e.
+func (s *State) end(e Ending) {
+	s.Ending = e
+	s.FailureCause = endingCauses[e]
+}
+
+// Stats are running totals and high-water marks for the end-of-run summary.
+type Stats struct {
+	Elapsed float64 // seconds of game time
+
+	PeakCash          int
+	PeakPricePerShare int
+	PeakUsers         int
+
+	FeaturesShipped int
+	BugsShipped     int
+	BugsFixed       int
+
+	Raised          int
+	TargetsMissed   int
+	SpentOnUpgrades int
+	Events          int
+
+	PeakDevs        int
+	PeakQA          int
+	PeakMarketers   int
+	PeakStrategists int
+	PeakInfluencers int
+}
+
+// track folds the latest state into the high-water marks.
+func (st Stats) track(s State) Stats {
+	st.PeakCash = max(st.PeakCash, s.Cash)
+	st.PeakPricePerShare = max(st.PeakPricePerShare, s.PricePerShare)
+	st.PeakUsers = max(st.PeakUsers, s.Users)
+	st.PeakDevs = max(st.PeakDevs, s.Devs)
+	st.PeakQA = max(st.PeakQA, s.QA)
+	st.PeakMarketers = max(st.PeakMarketers, s.Marketers)
+	st.PeakStrategists = max(st.PeakStrategists, s.Strategists)
+	st.PeakInfluencers = max(st.PeakInfluencers, s.Influencers)
+	return st
+}
@@ -0,0 +1,110 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoEvent is returned when choosing with no event waiting on the player.
+var ErrNoEvent = errors.New("no event to respond to")
+
+// Event is a card in the event deck: something that happens to the company
+// and the choices the founder has in how to respond.
+type Event struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+	// The event is only dealt to companies with at least this many devs and
+	// users, so nobody gets to lose a dev they never had.
+	MinDevs  int      `json:"min_devs,omitempty"`
+	MinUsers int      `json:"min_users,omitempty"`
+	Choices  []Choice `json:"choices"`
+}
+
+// Choice is one way to respond to an Event.
+type Choice struct {
+	Label  string `json:"label"`
+	Effect Effect `json:"effect"`
+}
+
+// Effect is what a Choice does to the company. UsersShare and PriceShare are
+// relative to the current users and share price; everything else is added
+// as is.
+type Effect struct {
+	Cash       int     `json:"cash,omitempty"`
+	Users      int     `json:"users,omitempty"`
+	UsersShare float64 `json:"users_share,omitempty"`
+	Bugs       int     `json:"bugs,omitempty"`
+	Devs       int     `json:"devs,omitempty"`
+	Market     int     `json:"market,omitempty"`
+	TechDebt   int     `json:"tech_debt,omitempty"`
+	PriceShare float64 `json:"price_share,omitempty"`
+}
+
+func (e Effect) apply(s State) State {
+	s.Cash += e.Cash
+	s.Market = max(0, s.Market+e.Market)
+	s.Users += e.Users + int(math.Round(float64(s.Users)*e.UsersShare))
+	s.Users = max(0, min(s.Market, s.Users))
+	s.Bugs = max(0, s.Bugs+e.Bugs)
+	s.Devs = max(0, s.Devs+e.Devs)
+	s.CopyPasteModifier = max(0, min(MAX_TECH_DEBT, s.CopyPasteModifier+e.TechDebt))
+	s.Quote += e.PriceShare * math.Abs(s.Quote)
+	s.PricePerShare = int(math.Round(s.Quote))
+	return s
+}
+
+// PendingEvent returns the event waiting on the player, if there is one.
+func PendingEvent(b Balance, s State) (Event, bool) {
+	if s.PendingEvent == "" {
+		return Event{}, false
+	}
+	for _, e := range b.Events {
+		if e.Name == s.PendingEvent {
+			return e, true
+		}
+	}
+	return Event{}, false
+}
+
+// Affordable reports whether the company has the cash c costs.
+func (c Choice) Affordable(s State) bool {
+	return c.Effect.Cash >= 0 || s.Cash+c.Effect.Cash >= 0
+}
+
+// Choose responds to the pending event with its choice'th choice.
+func Choose(b Balance, s State, choice int) (State, error) {
+	e, ok := PendingEvent(b, s)
+	if !ok {
+		return s, ErrNoEvent
+	}
+	if choice < 0 || choice >= len(e.Choices) {
+		return s, fmt.Errorf("%s has no choice %d", e.Name, choice)
+	}
+	c := e.Choices[choice]
+	if !c.Affordable(s) {
+		return s, fmt.Errorf("that costs $%d: %w", -c.Effect.Cash, ErrInsufficientCash)
+	}
+	s = c.Effect.apply(s)
+	s.PendingEvent = ""
+	return s, nil
+}
+
+// deal rolls for an event from the deck. Nothing new happens while the
+// player is still deciding on the last one.
+func deal(b Balance, s State, dt float64) State {
+	if s.PendingEvent != "" || len(b.Events) == 0 || b.EventChancePerSecond == 0 {
+		return s
+	}
+	if s.Rand.Float64() >= b.EventChancePerSecond*dt {
+		return s
+	}
+	e := b.Events[s.Rand.Intn(len(b.Events))]
+	// Drawing a card that doesn't apply makes for a quiet day.
+	if s.Devs < e.MinDevs || s.Users < e.MinUsers || len(e.Choices) == 0 {
+		return s
+	}
+	s.PendingEvent = e.Name
+	s.Stats.Events++
+	return s
+}
@@ -0,0 +1,136 @@
+package sim
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChoose(t *testing.T) {
+	b := DefaultBalance()
+	b.Events = []Event{{
+		Name: "Outage",
+		Choices: []Choice{
+			{Label: "Pay", Effect: Effect{Cash: -100}},
+			{Label: "Hotfix", Effect: Effect{Bugs: 3, TechDebt: 10}},
+			{Label: "Wait", Effect: Effect{UsersShare: -0.5}},
+		},
+	}}
+	tests := []struct {
+		name    string
+		s       State
+		choice  int
+		wantErr error
+		want    State
+	}{
+		{
+			name:    "nothing pending",
+			s:       State{Cash: 100},
+			wantErr: ErrNoEvent,
+			want:    State{Cash: 100},
+		},
+		{
+			name:    "a card no longer in the deck",
+			s:       State{PendingEvent: "Gone"},
+			wantErr: ErrNoEvent,
+			want:    State{PendingEvent: "Gone"},
+		},
+		{
+			name:    "can't afford it",
+			s:       State{Cash: 99, PendingEvent: "Outage"},
+			choice:  0,
+			wantErr: ErrInsufficientCash,
+			want:    State{Cash: 99, PendingEvent: "Outage"},
+		},
+		{
+			name:   "pays for it",
+			s:      State{Cash: 100, PendingEvent: "Outage"},
+			choice: 0,
+			want:   State{},
+		},
+		{
+			name:   "takes on bugs and debt",
+			s:      State{PendingEvent: "Outage"},
+			choice: 1,
+			want:   State{Bugs: 3, CopyPasteModifier: 10},
+		},
+		{
+			name:   "loses a share of users",
+			s:      State{Users: 10, Market: 100, PendingEvent: "Outage"},
+			choice: 2,
+			want:   State{Users: 5, Market: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Choose(b, tt.s, tt.choice)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if s != tt.want {
+				t.Errorf("Choose =\n%+v\nwant\n%+v", s, tt.want)
+			}
+		})
+	}
+
+	for _, choice := range []int{-1, 3} {
+		s := State{PendingEvent: "Outage"}
+		if _, err := Choose(b, s, choice); err == nil {
+			t.Errorf("Choose(%d) succeeded on an event with 3 choices", choice)
+		}
+	}
+}
+
+func TestDeal(t *testing.T) {
+	deck := []Event{{Name: "Poached", MinDevs: 1, Choices: []Choice{{Label: "Let them go"}}}}
+	tests := []struct {
+		name       string
+		chance     float64
+		s          State
+		wantEvent  string
+		wantEvents int
+		wantDraw   bool
+	}{
+		{
+			name:   "no chance",
+			chance: 0,
+			s:      State{Devs: 1},
+		},
+		{
+			name:      "still deciding on the last one",
+			chance:    1,
+			s:         State{Devs: 1, PendingEvent: "Earlier"},
+			wantEvent: "Earlier",
+		},
+		{
+			name:     "a card that doesn't apply is a quiet day",
+			chance:   1,
+			s:        State{Devs: 0},
+			wantDraw: true,
+		},
+		{
+			name:       "deals the card",
+			chance:     1,
+			s:          State{Devs: 1},
+			wantEvent:  "Poached",
+			wantEvents: 1,
+			wantDraw:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := DefaultBalance()
+			b.Events = deck
+			b.EventChancePerSecond = tt.chance
+			s := deal(b, tt.s, 1)
+			if s.PendingEvent != tt.wantEvent {
+				t.Errorf("PendingEvent = %q, want %q", s.PendingEvent, tt.wantEvent)
+			}
+			if s.Stats.Events != tt.wantEvents {
+				t.Errorf("Stats.Events = %d, want %d", s.Stats.Events, tt.wantEvents)
+			}
+			if drew := s.Rand != tt.s.Rand; drew != tt.wantDraw {
+				t.Errorf("drew from Rand = %v, want %v", drew, tt.wantDraw)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package sim
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrNoTermSheet is returned when raising money no investor is offering.
+	ErrNoTermSheet = errors.New("no term sheet on the table")
+	// ErrTargetPending is returned when raising again before the board's
+	// target from the last round has been met.
+	ErrTargetPending = errors.New("the board wants the last round's target met first")
+)
+
+// Round is a funding round investors will offer once the company is worth
+// enough.
+type Round struct {
+	Name string `json:"name"`
+	// Investors come knocking once PricePerShare reaches Price, offering
+	// Raise in cash for an Equity share of the company.
+	Price  int     `json:"price"`
+	Raise  int     `json:"raise"`
+	Equity float64 `json:"equity"`
+	// In return they expect PricePerShare to reach TargetMultiple times what
+	// it was at the close, within TargetSeconds.
+	TargetMultiple float64 `json:"target_multiple"`
+	TargetSeconds  float64 `json:"target_seconds"`
+}
+
+// TermSheet returns the next round and whether investors are offering it
+// right now. Nobody offers one while the last round's target is unmet.
+func TermSheet(b Balance, s State) (Round, bool) {
+	if s.RoundsRaised >= len(b.Rounds) {
+		return Round{}, false
+	}
+	r := b.Rounds[s.RoundsRaised]
+	return r, s.Target == 0 && s.PricePerShare >= r.Price
+}
+
+// Raise signs the term sheet on the table, trading equity for cash and
+// taking on the investors' growth target.
+func Raise(b Balance, s State) (State, error) {
+	if s.Target != 0 {
+		return s, ErrTargetPending
+	}
+	r, ok := TermSheet(b, s)
+	if !ok {
+		return s, ErrNoTermSheet
+	}
+	s.Cash += r.Raise
+	s.Ownership *= 1 - r.Equity
+	s.RoundsRaised++
+	s.Target = int(math.Ceil(float64(s.PricePerShare) * r.TargetMultiple))
+	s.TargetWindow = r.TargetSeconds
+	s.TargetDeadline = s.Stats.Elapsed + s.TargetWindow
+	s.Stats.Raised += r.Raise
+	return s, nil
+}
+
+// pitch notes the first time investors offer each round, so the offer is
+// news once rather than every time the price wanders back over the line.
+func pitch(b Balance, s State) State {
+	if _, offered := TermSheet(b, s); offered {
+		s.RoundsOffered = max(s.RoundsOffered, s.RoundsRaised+1)
+	}
+	return s
+}
+
+// board checks in on the growth target from the latest round. Hitting it
+// keeps the investors happy. Missing it costs the founder some of their stake
+// and the company some of its credibility, and the board sets a new deadline
+// for the same target.
+func board(b Balance, s State) State {
+	s.BoardPressure = false
+	if s.Target == 0 {
+		return s
+	}
+	if s.PricePerShare >= s.Target {
+		s.Target = 0
+		return s
+	}
+	if s.Stats.Elapsed < s.TargetDeadline {
+		return s
+	}
+	s.BoardPressure = true
+	s.Stats.TargetsMissed++
+	s.Ownership *= 1 - b.BoardClawback
+	s.Quote -= b.BoardPriceHit * math.Abs(s.Quote)
+	s.PricePerShare = int(math.Round(s.Quote))
+	s.TargetDeadline = s.Stats.Elapsed + s.TargetWindow
+	return s
+}
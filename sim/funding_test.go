@@ -0,0 +1,134 @@
+package sim
+
+import "testing"
+
+func TestRaise(t *testing.T) {
+	b := DefaultBalance()
+	seed := b.Rounds[0]
+	tests := []struct {
+		name    string
+		s       State
+		wantErr error
+		want    State
+	}{
+		{
+			name:    "nobody offers below the round's price",
+			s:       State{PricePerShare: seed.Price - 1, Ownership: 1},
+			wantErr: ErrNoTermSheet,
+			want:    State{PricePerShare: seed.Price - 1, Ownership: 1},
+		},
+		{
+			name:    "the board's target comes first",
+			s:       State{PricePerShare: seed.Price, Ownership: 1, Target: 1},
+			wantErr: ErrTargetPending,
+			want:    State{PricePerShare: seed.Price, Ownership: 1, Target: 1},
+		},
+		{
+			name:    "every round raised",
+			s:       State{PricePerShare: 1 << 30, Ownership: 1, RoundsRaised: len(b.Rounds)},
+			wantErr: ErrNoTermSheet,
+			want:    State{PricePerShare: 1 << 30, Ownership: 1, RoundsRaised: len(b.Rounds)},
+		},
+		{
+			name: "signs the seed round",
+			s:    State{PricePerShare: seed.Price, Ownership: 1, Stats: Stats{Elapsed: 10}},
+			want: State{
+				PricePerShare:  seed.Price,
+				Cash:           seed.Raise,
+				Ownership:      1 - seed.Equity,
+				RoundsRaised:   1,
+				Target:         int(float64(seed.Price) * seed.TargetMultiple),
+				TargetWindow:   seed.TargetSeconds,
+				TargetDeadline: 10 + seed.TargetSeconds,
+				Stats:          Stats{Elapsed: 10, Raised: seed.Raise},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Raise(b, tt.s)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if s != tt.want {
+				t.Errorf("Raise =\n%+v\nwant\n%+v", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoard(t *testing.T) {
+	b := DefaultBalance()
+	pending := State{
+		PricePerShare:  100,
+		Quote:          100,
+		Ownership:      0.9,
+		Target:         200,
+		TargetWindow:   60,
+		TargetDeadline: 60,
+	}
+	tests := []struct {
+		name    string
+		elapsed float64
+		price   int
+		want    func(s State) State
+	}{
+		{
+			name:    "target met",
+			elapsed: 30,
+			price:   200,
+			want: func(s State) State {
+				s.Target = 0
+				return s
+			},
+		},
+		{
+			name:    "still time on the clock",
+			elapsed: 59,
+			price:   100,
+			want:    func(s State) State { return s },
+		},
+		{
+			name:    "deadline missed",
+			elapsed: 60,
+			price:   100,
+			want: func(s State) State {
+				s.BoardPressure = true
+				s.Stats.TargetsMissed = 1
+				s.Ownership = 0.9 * (1 - b.BoardClawback)
+				s.Quote = 100 * (1 - b.BoardPriceHit)
+				s.PricePerShare = 90
+				s.TargetDeadline = 120
+				return s
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := pending
+			s.Stats.Elapsed = tt.elapsed
+			s.PricePerShare = tt.price
+			want := tt.want(s)
+			if got := board(b, s); got != want {
+				t.Errorf("board =\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+}
+
+func TestPitchAnnouncesEachRoundOnce(t *testing.T) {
+	b := DefaultBalance()
+	s := State{PricePerShare: b.Rounds[0].Price, Ownership: 1}
+	s = pitch(b, s)
+	if s.RoundsOffered != 1 {
+		t.Fatalf("RoundsOffered = %d after the seed price was reached, want 1", s.RoundsOffered)
+	}
+	// Dipping under the price and coming back isn't a new offer.
+	s.PricePerShare = 0
+	s = pitch(b, s)
+	s.PricePerShare = b.Rounds[0].Price
+	s = pitch(b, s)
+	if s.RoundsOffered != 1 {
+		t.Errorf("RoundsOffered = %d after the price came back, want 1", s.RoundsOffered)
+	}
+}
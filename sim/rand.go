@@ -0,0 +1,42 @@
+package sim
+
+import "math"
+
+// Rand is a splitmix64 generator. Its whole state is one number, so it can
+// live inside State, be copied along by Step and survive a save file, and a
+// run replays exactly from its seed.
+type Rand struct {
+	S uint64
+}
+
+func NewRand(seed int64) Rand {
+	return Rand{S: uint64(seed)}
+}
+
+func (r *Rand) Uint64() uint64 {
+	r.S += 0x9e3779b97f4a7c15
+	z := r.S
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Float64 returns a number in [0, 1).
+func (r *Rand) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+// Intn returns a number in [0, n). It panics if n <= 0.
+func (r *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic("sim: Intn with n <= 0")
+	}
+	return int(r.Uint64() % uint64(n))
+}
+
+// NormFloat64 returns a normally distributed number with mean 0 and standard
+// deviation 1.
+func (r *Rand) NormFloat64() float64 {
+	u := 1 - r.Float64() // (0, 1], so the log is finite
+	return math.Sqrt(-2*math.Log(u)) * math.Cos(2*math.Pi*r.Float64())
+}
@@ -0,0 +1,74 @@
+package sim
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRandReplaysFromSeed(t *testing.T) {
+	a, b := NewRand(7), NewRand(7)
+	for i := 0; i < 100; i++ {
+		if x, y := a.Uint64(), b.Uint64(); x != y {
+			t.Fatalf("draw %d: %d != %d from the same seed", i, x, y)
+		}
+	}
+	a, c := NewRand(7), NewRand(8)
+	if a.Uint64() == c.Uint64() {
+		t.Errorf("seeds 7 and 8 drew the same first number")
+	}
+}
+
+func TestRandRanges(t *testing.T) {
+	r := NewRand(1)
+	tests := []struct {
+		name string
+		draw func() float64
+		lo   float64
+		hi   float64 // exclusive
+	}{
+		{"Float64", r.Float64, 0, 1},
+		{"Intn(1)", func() float64 { return float64(r.Intn(1)) }, 0, 1},
+		{"Intn(6)", func() float64 { return float64(r.Intn(6)) }, 0, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10000; i++ {
+				if x := tt.draw(); x < tt.lo || x >= tt.hi {
+					t.Fatalf("draw %d = %v, want [%v, %v)", i, x, tt.lo, tt.hi)
+				}
+			}
+		})
+	}
+}
+
+func TestRandNormFloat64(t *testing.T) {
+	const n = 100000
+	r := NewRand(1)
+	var sum, sumSq float64
+	for i := 0; i < n; i++ {
+		x := r.NormFloat64()
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			t.Fatalf("draw %d = %v", i, x)
+		}
+		sum += x
+		sumSq += x * x
+	}
+	mean := sum / n
+	sd := math.Sqrt(sumSq/n - mean*mean)
+	if math.Abs(mean) > 0.02 {
+		t.Errorf("mean = %v, want about 0", mean)
+	}
+	if math.Abs(sd-1) > 0.02 {
+		t.Errorf("standard deviation = %v, want about 1", sd)
+	}
+}
+
+func TestRandIntnPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Intn(0) didn't panic")
+		}
+	}()
+	r := NewRand(1)
+	r.Intn(0)
+}
@@ -0,0 +1,290 @@
+// Package sim holds the economy of The Startup(tm), with no dependency on the
+// terminal UI. The bubbletea model embeds a State and advances it with Step
+// under a Balance, so bots, balance scripts and the game itself all run the
+// same rules.
+package sim
+
+import "math"
+
+// MAX_DEV_FOCUS is the DevFocus at which devs work only on new features; at
+// zero they only fix bugs.
+const MAX_DEV_FOCUS = 10
+
+// State is everything the simulation needs to advance one step.
+type State struct {
+	PricePerShare               int
+	Cash                        int
+	CashPerSecond               int
+	RevenuePerSecond            int
+	SalariesPerSecond           int
+	Users                       int
+	UsersPerSecondFromFeatures  float64
+	UsersPerSecondFromMarketers float64
+	UsersPerSecondFromBugs      float64
+	Features                    int
+	FeaturesPerSecond           float64
+	Bugs                        int
+	BugsPerSecondPerFeature     float64
+	BugsPerSecondPerDev         float64
+	BugsFixedPerSecond          float64
+	BugsFixedPerSecondByQA      float64
+	FeatureDragFromQA           float64 // share of dev feature output lost to QA process
+	Devs                        int
+	QA                          int
+	Marketers                   int
+	Strategists                 int
+	Influencers                 int
+
+	// Upgrades bought from the shop.
+	Offices      int
+	Servers      int
+	Perks        int
+	Acquisitions int
+
+	// Market is how many users could ever sign up; Users never exceeds it.
+	Market          int
+	MarketPerSecond float64
+
+	// ViralChancePerSecond is how likely an influencer spike is each second;
+	// LastSpike is the users won (or, in a backlash, lost) by a spike during
+	// the latest Step, zero if there wasn't one.
+	ViralChancePerSecond float64
+	LastSpike            int
+
+	// PricePerShare is Quote rounded. Quote chases FundamentalValue with
+	// PriceMomentum (per second); LastNews is how far a news story moved it
+	// during the latest Step, zero if there wasn't one. CrashSeconds is how
+	// long the price has been below the collapse price without a break.
+	FundamentalValue int
+	Quote            float64
+	PriceMomentum    float64
+	LastNews         int
+	CrashSeconds     float64
+
+	// Ownership is the founder's share of the company, diluted by every round
+	// raised. After a round the board wants PricePerShare at Target by
+	// TargetDeadline (in Stats.Elapsed seconds); Target is zero when there's
+	// nothing left to prove. BoardPressure is set on the Step the board found
+	// the target missed.
+	Ownership      float64
+	RoundsRaised   int
+	RoundsOffered  int // rounds investors have offered at some point
+	Target         int
+	TargetDeadline float64
+	TargetWindow   float64
+	BoardPressure  bool
+
+	ProgressTowardFeature  float64
+	ProgressTowardBug      float64
+	ProgressTowardBugFix   float64
+	ProgressTowardUser     float64
+	ProgressTowardLostUser float64
+	ProgressTowardCash     float64
+	ProgressTowardMarket   float64
+	ProgressTowardDebt     float64
+
+	// CopyPasteModifier is the tech debt meter, 0..MAX_TECH_DEBT, built up
+	// by shipping features in a hurry; TechDebtPerSecond is how fast it's
+	// moving. RefactorSeconds is what's left of the current refactor sprint,
+	// zero when there isn't one.
+	CopyPasteModifier int
+	TechDebtPerSecond float64
+	RefactorSeconds   float64
+
+	DevFocus int // 0..MAX_DEV_FOCUS, how much dev time goes to features over bugs
+
+	// PendingEvent names the event from the deck waiting on the player's
+	// choice, empty when there isn't one.
+	PendingEvent string
+
+	// Seed started Rand; every random thing in the simulation draws from Rand.
+	Seed int64
+	Rand Rand
+
+	Stats Stats
+
+	// Ending is how the run finished, and FailureCause explains it to the
+	// player; both are set once the run is over.
+	Ending       Ending
+	FailureCause string
+}
+
+// New returns the state a fresh run under b seeded with seed starts from.
+func New(b Balance, seed int64) State {
+	s := State{
+		Users:     1,
+		Market:    b.StartingMarket,
+		DevFocus:  MAX_DEV_FOCUS,
+		Ownership: 1,
+		Seed:      seed,
+		Rand:      NewRand(seed),
+	}
+	// The market opens at what the company is worth.
+	s.FundamentalValue = b.Fundamental(s)
+	s.Quote = float64(s.FundamentalValue)
+	s.PricePerShare = s.FundamentalValue
+	return s
+}
+
+// Over reports whether the run has hit a loss condition.
+func (s State) Over() bool {
+	return s.Ending != Running
+}
+
+// drain removes the whole units accumulated in progress and returns them.
+func drain(progress *float64) int {
+	whole := math.Floor(*progress)
+	*progress -= whole
+	return int(whole)
+}
+
+// FocusFeatures shifts one notch of dev time from bug fixing to features.
+func FocusFeatures(s State) State {
+	s.DevFocus = min(MAX_DEV_FOCUS, s.DevFocus+1)
+	return s
+}
+
+// FocusBugs shifts one notch of dev time from features to bug fixing.
+func FocusBugs(s State) State {
+	s.DevFocus = max(0, s.DevFocus-1)
+	return s
+}
+
+// ShipFeatureByHand has the founder finish the feature in progress themselves,
+// cutting whatever corners it takes.
+func ShipFeatureByHand(b Balance, s State) State {
+	if s.ProgressTowardFeature < 1 {
+		s.ProgressTowardDebt += b.TechDebtPerFeatureByHand
+	}
+	s.ProgressTowardFeature = 1.
+	return s
+}
+
+// FixBugByHand has the founder squash one bug themselves.
+func FixBugByHand(s State) State {
+	if s.Bugs > 0 {
+		s.Bugs--
+		s.Stats.BugsFixed++
+	}
+	return s
+}
+
+// influence rolls for an influencer post going viral.
+func influence(b Balance, s State, dt float64) State {
+	s.LastSpike = 0
+	s.ViralChancePerSecond = float64(s.Influencers) * b.ViralChancePerSecondPerInfluencer
+	if s.Influencers == 0 || s.Rand.Float64() >= s.ViralChancePerSecond*dt {
+		return s
+	}
+	if s.Bugs >= b.BacklashBugs {
+		s.LastSpike = -int(math.Ceil(float64(s.Users) * b.BacklashShareOfUsers))
+	} else {
+		s.LastSpike = int(math.Ceil(float64(s.Market-s.Users) * b.ViralShareOfMarket))
+	}
+	s.Users = max(0, min(s.Market, s.Users+s.LastSpike))
+	return s
+}
+
+// Step advances s by dt seconds of game time under balance b.
+func Step(b Balance, s State, dt float64) State {
+	if s.Over() {
+		return s
+	}
+
+	featureShare := float64(s.DevFocus) / MAX_DEV_FOCUS
+	bugShare := 1 - featureShare
+	// Nobody ships or fixes anything while the team is refactoring.
+	if s.RefactorSeconds > 0 {
+		featureShare, bugShare = 0, 0
+	}
+
+	// Every QA adds review and sign-off that devs have to wait on.
+	s.FeatureDragFromQA = 1 - 1/(1+b.FeatureDragPerQA*float64(s.QA))
+	s.FeaturesPerSecond = float64(s.Devs) * b.FeaturesPerSecondPerDev * b.productivity(s) * featureShare * (1 - s.FeatureDragFromQA)
+	s.ProgressTowardFeature += s.FeaturesPerSecond * dt
+	newFeatures := drain(&s.ProgressTowardFeature)
+	s.Features += newFeatures
+	s.Stats.FeaturesShipped += newFeatures
+
+	s.BugsFixedPerSecondByQA = float64(s.QA) * b.BugsFixedPerSecondPerQA
+	s.BugsFixedPerSecond = s.BugsFixedPerSecondByQA +
+		float64(s.Devs)*b.BugsFixedPerSecondPerDev*b.productivity(s)*bugShare
+	s.ProgressTowardBugFix += s.BugsFixedPerSecond * dt
+	fixed := min(s.Bugs, drain(&s.ProgressTowardBugFix))
+	s.Bugs -= fixed
+	s.Stats.BugsFixed += fixed
+
+	s.BugsPerSecondPerDev = float64(s.Devs) * b.BugsPerSecondPerDev
+	s = accrue(b, s, featureShare, dt)
+	s.BugsPerSecondPerFeature = float64(s.Features) * b.BugsPerSecondPerFeature * b.debtBugMultiplier(s)
+	bugsPerSecond := s.BugsPerSecondPerDev + s.BugsPerSecondPerFeature
+	s.ProgressTowardBug += bugsPerSecond * dt
+	newBugs := drain(&s.ProgressTowardBug)
+	s.Bugs += newBugs
+	s.Stats.BugsShipped += newBugs
+
+	s.MarketPerSecond = float64(s.Strategists) * b.MarketPerSecondPerStrategist
+	s.ProgressTowardMarket += s.MarketPerSecond * dt
+	s.Market += drain(&s.ProgressTowardMarket)
+
+	// Adoption slows as the market saturates: the last few holdouts are the
+	// hardest to win over.
+	unadopted := 0.
+	if s.Market > 0 {
+		unadopted = math.Max(0, 1-float64(s.Users)/float64(s.Market))
+	}
+	s.UsersPerSecondFromFeatures = float64(s.Features) * b.UsersPerSecondPerFeature * unadopted
+	s.UsersPerSecondFromMarketers = float64(s.Marketers) * b.UsersPerSecondPerMarketer * unadopted
+	usersAddedPerSecond := s.UsersPerSecondFromFeatures + s.UsersPerSecondFromMarketers
+	s.ProgressTowardUser += usersAddedPerSecond * dt
+	s.Users = min(s.Market, s.Users+drain(&s.ProgressTowardUser))
+
+	s = influence(b, s, dt)
+
+	s.UsersPerSecondFromBugs = float64(s.Bugs) * b.UsersPerSecondPerBug * b.uptime(s)
+	s.ProgressTowardLostUser += s.UsersPerSecondFromBugs * dt
+	s.Users = max(0, s.Users-drain(&s.ProgressTowardLostUser))
+
+	s.RevenuePerSecond = b.CashPerSecondPerUserPerFeature * s.Users * s.Features
+	s.SalariesPerSecond = b.Payroll(s)
+	s.CashPerSecond = s.RevenuePerSecond - s.SalariesPerSecond
+	s.ProgressTowardCash += float64(s.CashPerSecond) * dt
+	s.Cash += drain(&s.ProgressTowardCash)
+
+	s = trade(b, s, dt)
+
+	s.Stats.Elapsed += dt
+	s.Stats = s.Stats.track(s)
+
+	s = board(b, s)
+	s = pitch(b, s)
+
+	if s.Cash > b.CashCap {
+		s.end(Crushed)
+	}
+
+	// One bad day doesn't sink the company; a crash that won't let up does.
+	// The market gives a new company a while to find its feet first.
+	if s.PricePerShare < b.CollapsePrice && s.Stats.Elapsed > b.CollapseGraceSeconds {
+		s.CrashSeconds += dt
+		if s.CrashSeconds >= b.CollapseSeconds {
+			s.end(Collapsed)
+		}
+	} else {
+		s.CrashSeconds = 0
+	}
+
+	if s.Cash < 0 {
+		s.end(Bankrupt)
+	}
+
+	if s.Ownership < b.OustedBelowOwnership {
+		s.end(Ousted)
+	}
+
+	if !s.Over() {
+		s = deal(b, s, dt)
+	}
+
+	return s
+}
@@ -0,0 +1,144 @@
+package sim
+
+import "testing"
+
+// calm is the default balance with every source of luck turned off, so a
+// test can work its numbers out by hand.
+func calm() Balance {
+	b := DefaultBalance()
+	b.PriceVolatility = 0
+	b.NewsChancePerSecond = 0
+	b.ViralChancePerSecondPerInfluencer = 0
+	b.EventChancePerSecond = 0
+	return b
+}
+
+func TestStep(t *testing.T) {
+	b := calm()
+	tests := []struct {
+		name   string
+		setup  func(s *State)
+		ending Ending
+		got    func(s State) int
+		want   int
+	}{
+		{
+			name:  "devs ship features",
+			setup: func(s *State) { s.Cash, s.Devs = 100, 2 },
+			got:   func(s State) int { return s.Features },
+			want:  1,
+		},
+		{
+			name:  "salaries come out of cash",
+			setup: func(s *State) { s.Cash, s.Devs = 100, 2 },
+			got:   func(s State) int { return s.Cash },
+			// $1 revenue from one user on one feature, $2 of salaries.
+			want: 99,
+		},
+		{
+			name:  "qa fix bugs",
+			setup: func(s *State) { s.Cash, s.QA, s.Bugs, s.Users = 100, 60, 5, 50 },
+			got:   func(s State) int { return s.Bugs },
+			want:  4,
+		},
+		{
+			name:  "bugs drive users away",
+			setup: func(s *State) { s.Cash, s.Users, s.Bugs, s.Marketers = 100, 20, 8, 4 },
+			// One user lost to bugs. Marketing is most of the way to winning
+			// one back on its own accumulator, but not there yet.
+			got:  func(s State) int { return s.Users },
+			want: 19,
+		},
+		{
+			name:   "cash over the cap is crushing",
+			setup:  func(s *State) { s.Cash = b.CashCap + 1 },
+			ending: Crushed,
+			got:    func(s State) int { return s.Cash },
+			want:   b.CashCap + 1,
+		},
+		{
+			name:   "payroll past cash is bankruptcy",
+			setup:  func(s *State) { s.Devs = 1 },
+			ending: Bankrupt,
+			got:    func(s State) int { return s.Cash },
+			want:   -1,
+		},
+		{
+			name:   "too little of the company is ousting",
+			setup:  func(s *State) { s.Ownership = b.OustedBelowOwnership / 2 },
+			ending: Ousted,
+			got:    func(s State) int { return s.Cash },
+			want:   0,
+		},
+		{
+			name:   "a finished run stays put",
+			setup:  func(s *State) { s.Devs, s.Ending = 10, Bankrupt },
+			ending: Bankrupt,
+			got:    func(s State) int { return s.Features },
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(b, 1)
+			tt.setup(&s)
+			s = Step(b, s, 1)
+			if s.Ending != tt.ending {
+				t.Errorf("Ending = %v, want %v", s.Ending, tt.ending)
+			}
+			if got := tt.got(s); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStepCollapse(t *testing.T) {
+	tests := []struct {
+		name          string
+		collapsePrice int
+		seconds       float64
+		grace         float64
+		steps         int
+		want          Ending
+	}{
+		{"a bad spell", 1000, 10, 0, 9, Running},
+		{"a crash that won't let up", 1000, 10, 0, 10, Collapsed},
+		{"no time to recover", 1000, 0, 0, 1, Collapsed},
+		{"no time to recover, but nothing to recover from", 0, 0, 0, 1, Running},
+		{"still finding its feet", 1000, 10, 60, 69, Running},
+		{"out of grace", 1000, 10, 60, 70, Collapsed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := calm()
+			b.CollapsePrice = tt.collapsePrice
+			b.CollapseSeconds = tt.seconds
+			b.CollapseGraceSeconds = tt.grace
+			s := New(b, 1)
+			for i := 0; i < tt.steps; i++ {
+				s = Step(b, s, 1)
+			}
+			if s.Ending != tt.want {
+				t.Errorf("Ending = %v after %ds at $%d, want %v", s.Ending, tt.steps, s.PricePerShare, tt.want)
+			}
+		})
+	}
+}
+
+func TestStepIsDeterministic(t *testing.T) {
+	b := DefaultBalance()
+	run := func() State {
+		s := New(b, 42)
+		s.Cash, s.Devs, s.Influencers = 1000, 3, 2
+		for i := 0; i < 200 && !s.Over(); i++ {
+			s = Step(b, s, 1)
+			s.PendingEvent = ""
+		}
+		return s
+	}
+	first, second := run(), run()
+	if first != second {
+		t.Errorf("two runs from the same seed differ:\n%+v\n%+v", first, second)
+	}
+}
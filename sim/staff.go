@@ -0,0 +1,117 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInsufficientCash is returned when a hire or upgrade costs more than the
+// company has.
+var ErrInsufficientCash = errors.New("not enough cash")
+
+// Role is a kind of employee the founder can hire.
+type Role int
+
+const (
+	RoleDev Role = iota
+	RoleQA
+	RoleMarketer
+	RoleStrategist
+	RoleInfluencer
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleDev:
+		return "dev"
+	case RoleQA:
+		return "qa"
+	case RoleMarketer:
+		return "marketer"
+	case RoleStrategist:
+		return "strategist"
+	case RoleInfluencer:
+		return "influencer"
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// SigningCost is the one-off cash paid when hiring r.
+func (b Balance) SigningCost(r Role) int {
+	switch r {
+	case RoleDev:
+		return b.DevSigningCost
+	case RoleQA:
+		return b.QASigningCost
+	case RoleMarketer:
+		return b.MarketerSigningCost
+	case RoleStrategist:
+		return b.StrategistSigningCost
+	case RoleInfluencer:
+		return b.InfluencerSigningCost
+	}
+	return 0
+}
+
+// Salary is the cash paid each second for every employee of role r.
+func (b Balance) Salary(r Role) int {
+	switch r {
+	case RoleDev:
+		return b.DevSalaryPerSecond
+	case RoleQA:
+		return b.QASalaryPerSecond
+	case RoleMarketer:
+		return b.MarketerSalaryPerSecond
+	case RoleStrategist:
+		return b.StrategistSalaryPerSecond
+	case RoleInfluencer:
+		return b.InfluencerSalaryPerSecond
+	}
+	return 0
+}
+
+// headcount returns the counter tracking how many of r are employed.
+func (s *State) headcount(r Role) *int {
+	switch r {
+	case RoleDev:
+		return &s.Devs
+	case RoleQA:
+		return &s.QA
+	case RoleMarketer:
+		return &s.Marketers
+	case RoleStrategist:
+		return &s.Strategists
+	case RoleInfluencer:
+		return &s.Influencers
+	}
+	panic(fmt.Sprintf("sim: unknown role %d", int(r)))
+}
+
+// Payroll is the total salary bill per second for the staff in s.
+func (b Balance) Payroll(s State) int {
+	return s.Devs*b.Salary(RoleDev) +
+		s.QA*b.Salary(RoleQA) +
+		s.Marketers*b.Salary(RoleMarketer) +
+		s.Strategists*b.Salary(RoleStrategist) +
+		s.Influencers*b.Salary(RoleInfluencer)
+}
+
+// Hire adds one employee of role r, paying their signing cost up front. If
+// the company can't afford it, s is returned unchanged with
+// ErrInsufficientCash.
+func Hire(b Balance, s State, r Role) (State, error) {
+	cost := b.SigningCost(r)
+	if s.Cash < cost {
+		return s, fmt.Errorf("hiring a %v costs $%d: %w", r, cost, ErrInsufficientCash)
+	}
+	s.Cash -= cost
+	*s.headcount(r) += 1
+	return s, nil
+}
+
+// Fire lets one employee of role r go. Firing from an empty team is a no-op.
+func Fire(s State, r Role) State {
+	n := s.headcount(r)
+	*n = max(0, *n-1)
+	return s
+}
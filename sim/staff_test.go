@@ -0,0 +1,82 @@
+package sim
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHire(t *testing.T) {
+	b := DefaultBalance()
+	tests := []struct {
+		name      string
+		cash      int
+		role      Role
+		wantErr   error
+		wantCash  int
+		wantCount int
+	}{
+		{"dev with exact cash", b.DevSigningCost, RoleDev, nil, 0, 1},
+		{"marketer with spare cash", b.MarketerSigningCost + 7, RoleMarketer, nil, 7, 1},
+		{"dev a dollar short", b.DevSigningCost - 1, RoleDev, ErrInsufficientCash, b.DevSigningCost - 1, 0},
+		{"qa when broke", 0, RoleQA, ErrInsufficientCash, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Hire(b, State{Cash: tt.cash}, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if s.Cash != tt.wantCash {
+				t.Errorf("Cash = %d, want %d", s.Cash, tt.wantCash)
+			}
+			if got := *s.headcount(tt.role); got != tt.wantCount {
+				t.Errorf("%v headcount = %d, want %d", tt.role, got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestFire(t *testing.T) {
+	tests := []struct {
+		name string
+		s    State
+		role Role
+		want int
+	}{
+		{"one of several", State{Devs: 3}, RoleDev, 2},
+		{"the last one", State{QA: 1}, RoleQA, 0},
+		{"from an empty team", State{}, RoleMarketer, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Fire(tt.s, tt.role)
+			if got := *s.headcount(tt.role); got != tt.want {
+				t.Errorf("%v headcount = %d, want %d", tt.role, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayroll(t *testing.T) {
+	b := DefaultBalance()
+	tests := []struct {
+		name string
+		s    State
+		want int
+	}{
+		{"nobody", State{}, 0},
+		{"devs only", State{Devs: 4}, 4 * b.DevSalaryPerSecond},
+		{
+			"everyone",
+			State{Devs: 1, QA: 2, Marketers: 3},
+			b.DevSalaryPerSecond + 2*b.QASalaryPerSecond + 3*b.MarketerSalaryPerSecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Payroll(tt.s); got != tt.want {
+				t.Errorf("Payroll = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package sim
+
+import "math"
+
+// Fundamental is what s is worth on paper under b: a weighted sum of what the
+// company has built, who it employs and the real estate it holds. The share
+// price chases it but rarely sits on it.
+func (b Balance) Fundamental(s State) int {
+	return b.PricePerFeature*s.Features +
+		b.PricePerDev*s.Devs +
+		b.PricePerBug*s.Bugs +
+		b.PricePerUser*s.Users +
+		b.PricePerMarketer*s.Marketers +
+		b.PricePerStrategist*s.Strategists +
+		b.PricePerInfluencer*s.Influencers +
+		b.PricePerOffice*s.Offices
+}
+
+// trade moves the share price one step. The price is pulled toward the
+// fundamental value like a weight on a spring, so it builds momentum and
+// overshoots; on top of that it wanders by a little every second and now and
+// then jumps on a news story.
+func trade(b Balance, s State, dt float64) State {
+	s.FundamentalValue = b.Fundamental(s)
+	gap := float64(s.FundamentalValue) - s.Quote
+	s.PriceMomentum += (b.PricePullPerSecond*gap - b.PriceDampingPerSecond*s.PriceMomentum) * dt
+
+	// Moves are relative to the price, with a floor so a penny stock still
+	// trades.
+	scale := math.Max(math.Abs(s.Quote), b.PriceNoiseFloor)
+	s.Quote += s.PriceMomentum*dt + b.PriceVolatility*scale*math.Sqrt(dt)*s.Rand.NormFloat64()
+
+	s.LastNews = 0
+	if s.Rand.Float64() < b.NewsChancePerSecond*dt {
+		shock := b.NewsShockShare * scale
+		if s.Rand.Float64() < 0.5 {
+			shock = -shock
+		}
+		s.Quote += shock
+		s.LastNews = int(math.Round(shock))
+	}
+
+	s.PricePerShare = int(math.Round(s.Quote))
+	return s
+}
@@ -0,0 +1,26 @@
+package sim
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFundamental(t *testing.T) {
+	b := DefaultBalance()
+	s := State{Features: 2, Users: 3, Bugs: 1, Devs: 1, Offices: 1}
+	want := 2*b.PricePerFeature + 3*b.PricePerUser + b.PricePerBug + b.PricePerDev + b.PricePerOffice
+	if got := b.Fundamental(s); got != want {
+		t.Errorf("Fundamental = %d, want %d", got, want)
+	}
+}
+
+func TestTradeSettlesOnFundamental(t *testing.T) {
+	b := calm()
+	s := State{Users: 10}
+	for i := 0; i < 200; i++ {
+		s = trade(b, s, 1)
+	}
+	if math.Abs(s.Quote-float64(s.FundamentalValue)) > 1 {
+		t.Errorf("Quote = %v after 200s without noise, want it settled on %d", s.Quote, s.FundamentalValue)
+	}
+}
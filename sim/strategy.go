@@ -0,0 +1,128 @@
+package sim
+
+// Strategy is a scripted player. Before every step it gets the current state
+// and returns it with whatever hiring, firing or clicking it wants done.
+type Strategy func(b Balance, s State) State
+
+// Combine runs each strategy in turn, feeding each the previous one's result.
+func Combine(strategies ...Strategy) Strategy {
+	return func(b Balance, s State) State {
+		for _, strategy := range strategies {
+			s = strategy(b, s)
+		}
+		return s
+	}
+}
+
+// Idle never touches anything.
+func Idle(b Balance, s State) State {
+	return s
+}
+
+// Mash ships features by hand every step until there are devs to do it,
+// like a player hammering the feature keys to get off the ground.
+func Mash(b Balance, s State) State {
+	if s.Devs == 0 {
+		s = ShipFeatureByHand(b, s)
+	}
+	return s
+}
+
+// HireDevsAbove hires a dev whenever cash is over threshold.
+func HireDevsAbove(threshold int) Strategy {
+	return hireAbove(RoleDev, threshold)
+}
+
+// HireMarketersAbove hires a marketer whenever cash is over threshold.
+func HireMarketersAbove(threshold int) Strategy {
+	return hireAbove(RoleMarketer, threshold)
+}
+
+// HireStrategistsAbove hires a business strategist whenever cash is over
+// threshold.
+func HireStrategistsAbove(threshold int) Strategy {
+	return hireAbove(RoleStrategist, threshold)
+}
+
+// HireInfluencersAbove hires an influencer whenever cash is over threshold.
+func HireInfluencersAbove(threshold int) Strategy {
+	return hireAbove(RoleInfluencer, threshold)
+}
+
+func hireAbove(r Role, threshold int) Strategy {
+	return func(b Balance, s State) State {
+		if s.Cash > threshold {
+			s, _ = Hire(b, s, r)
+		}
+		return s
+	}
+}
+
+// RaiseWhenOffered signs every term sheet as soon as investors offer one.
+func RaiseWhenOffered(b Balance, s State) State {
+	s, _ = Raise(b, s)
+	return s
+}
+
+// RefactorAbove starts a refactor sprint whenever tech debt is over threshold.
+func RefactorAbove(threshold int) Strategy {
+	return func(b Balance, s State) State {
+		if s.CopyPasteModifier > threshold {
+			s, _ = StartRefactor(b, s)
+		}
+		return s
+	}
+}
+
+// TakeFirstChoice answers every event with the first choice it can afford.
+func TakeFirstChoice(b Balance, s State) State {
+	e, ok := PendingEvent(b, s)
+	if !ok {
+		return s
+	}
+	for i, c := range e.Choices {
+		if c.Affordable(s) {
+			s, _ = Choose(b, s, i)
+			break
+		}
+	}
+	return s
+}
+
+// KeepQARatio hires QA so there is at least one for every devsPerQA devs.
+func KeepQARatio(devsPerQA int) Strategy {
+	return func(b Balance, s State) State {
+		if devsPerQA > 0 && s.QA*devsPerQA < s.Devs {
+			s, _ = Hire(b, s, RoleQA)
+		}
+		return s
+	}
+}
+
+// Result summarises a headless run.
+type Result struct {
+	Ticks             int
+	FailureCause      string
+	PeakPricePerShare int
+	PeakCash          int
+}
+
+// Run plays strategy from s for up to maxTicks steps of dt seconds, stopping
+// early if the run ends. observe, if not nil, sees the state after every step.
+// The game doesn't move on until the player answers an event, so any event
+// strategy leaves pending is answered with TakeFirstChoice.
+func Run(b Balance, s State, strategy Strategy, dt float64, maxTicks int, observe func(tick int, s State)) (State, Result) {
+	var r Result
+	for r.Ticks < maxTicks && !s.Over() {
+		s = TakeFirstChoice(b, strategy(b, s))
+		s = Step(b, s, dt)
+		r.Ticks++
+		if observe != nil {
+			observe(r.Ticks, s)
+		}
+	}
+	r.FailureCause = s.FailureCause
+	r.PeakPricePerShare = s.Stats.PeakPricePerShare
+	r.PeakCash = s.Stats.PeakCash
+	return s, r
+}
@@ -0,0 +1,27 @@
+package sim
+
+import "testing"
+
+func TestRunAnswersEvents(t *testing.T) {
+	b := calm()
+	b.EventChancePerSecond = 1
+	b.Events = []Event{{
+		Name: "Meetup",
+		Choices: []Choice{
+			{Label: "Sponsor it", Effect: Effect{Cash: -100}},
+			{Label: "Go along", Effect: Effect{Users: 1}},
+		},
+	}}
+	s, r := Run(b, New(b, 1), Idle, 1, 10, nil)
+	if r.Ticks != 10 {
+		t.Fatalf("ran %d ticks, want 10", r.Ticks)
+	}
+	// One event a step, each answered with the only choice a broke company
+	// can afford before the next is dealt.
+	if s.Stats.Events != 10 {
+		t.Errorf("Stats.Events = %d, want 10", s.Stats.Events)
+	}
+	if s.Users != 1+9 {
+		t.Errorf("Users = %d, want 10", s.Users)
+	}
+}
@@ -0,0 +1,120 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+)
+
+// Upgrade is something the company can buy, as many times as it can afford.
+type Upgrade int
+
+const (
+	UpgradeOffice Upgrade = iota
+	UpgradeServers
+	UpgradePerks
+	UpgradeAcquisition
+)
+
+// Upgrades lists everything in the shop, in the order it's shown.
+var Upgrades = []Upgrade{UpgradeOffice, UpgradeServers, UpgradePerks, UpgradeAcquisition}
+
+func (u Upgrade) String() string {
+	switch u {
+	case UpgradeOffice:
+		return "office"
+	case UpgradeServers:
+		return "servers"
+	case UpgradePerks:
+		return "perks"
+	case UpgradeAcquisition:
+		return "acquisition"
+	}
+	return fmt.Sprintf("Upgrade(%d)", int(u))
+}
+
+// baseCost is what the first u costs.
+func (b Balance) baseCost(u Upgrade) int {
+	switch u {
+	case UpgradeOffice:
+		return b.OfficeCost
+	case UpgradeServers:
+		return b.ServersCost
+	case UpgradePerks:
+		return b.PerksCost
+	case UpgradeAcquisition:
+		return b.AcquisitionCost
+	}
+	return 0
+}
+
+// UpgradeCost is what the next u costs, going up with every one bought.
+func (b Balance) UpgradeCost(s State, u Upgrade) int {
+	owned := s.owned(u)
+	if owned == nil {
+		return 0
+	}
+	return int(math.Round(float64(b.baseCost(u)) * math.Pow(b.UpgradeCostGrowth, float64(*owned))))
+}
+
+// owned returns the counter tracking how many of u have been bought.
+func (s *State) owned(u Upgrade) *int {
+	switch u {
+	case UpgradeOffice:
+		return &s.Offices
+	case UpgradeServers:
+		return &s.Servers
+	case UpgradePerks:
+		return &s.Perks
+	case UpgradeAcquisition:
+		return &s.Acquisitions
+	}
+	return nil
+}
+
+// Owned is how many of u the company has bought.
+func (s State) Owned(u Upgrade) int {
+	if n := s.owned(u); n != nil {
+		return *n
+	}
+	return 0
+}
+
+// Buy pays for one more u and puts it to work. An office brings the company
+// to the attention of more of the market; an acquisition brings the rival's
+// features, users and bugs with it. Servers and perks pay off over time, in
+// Step.
+func Buy(b Balance, s State, u Upgrade) (State, error) {
+	owned := s.owned(u)
+	if owned == nil {
+		return s, fmt.Errorf("unknown upgrade %v", u)
+	}
+	cost := b.UpgradeCost(s, u)
+	if s.Cash < cost {
+		return s, fmt.Errorf("the next %v costs $%d: %w", u, cost, ErrInsufficientCash)
+	}
+	s.Cash -= cost
+	s.Stats.SpentOnUpgrades += cost
+	*owned++
+
+	switch u {
+	case UpgradeOffice:
+		s.Market += b.MarketPerOffice
+	case UpgradeAcquisition:
+		s.Features += b.AcquisitionFeatures
+		s.Bugs += b.AcquisitionBugs
+		s.Market += b.AcquisitionUsers
+		s.Users += b.AcquisitionUsers
+	}
+	return s, nil
+}
+
+// productivity is how much more each dev gets done thanks to perks.
+func (b Balance) productivity(s State) float64 {
+	return 1 + b.ProductivityPerPerk*float64(s.Perks)
+}
+
+// uptime scales down the users lost to bugs, as servers keep the worst of
+// them from taking the product down.
+func (b Balance) uptime(s State) float64 {
+	return 1 / (1 + b.ChurnCutPerServer*float64(s.Servers))
+}
@@ -0,0 +1,84 @@
+package sim
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpgradeCost(t *testing.T) {
+	b := DefaultBalance()
+	tests := []struct {
+		name string
+		s    State
+		u    Upgrade
+		want int
+	}{
+		{"first perks", State{}, UpgradePerks, b.PerksCost},
+		{"second perks", State{Perks: 1}, UpgradePerks, 1500},
+		{"third perks", State{Perks: 2}, UpgradePerks, 2250},
+		{"first office", State{Offices: 0}, UpgradeOffice, b.OfficeCost},
+		{"unknown upgrade", State{}, Upgrade(-1), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.UpgradeCost(tt.s, tt.u); got != tt.want {
+				t.Errorf("UpgradeCost = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuy(t *testing.T) {
+	b := DefaultBalance()
+	tests := []struct {
+		name    string
+		s       State
+		u       Upgrade
+		wantErr error
+		want    State
+	}{
+		{
+			name: "an office grows the market",
+			s:    State{Cash: b.OfficeCost, Market: 1000},
+			u:    UpgradeOffice,
+			want: State{Offices: 1, Market: 1000 + b.MarketPerOffice, Stats: Stats{SpentOnUpgrades: b.OfficeCost}},
+		},
+		{
+			name: "servers only pay off in Step",
+			s:    State{Cash: b.ServersCost + 1},
+			u:    UpgradeServers,
+			want: State{Cash: 1, Servers: 1, Stats: Stats{SpentOnUpgrades: b.ServersCost}},
+		},
+		{
+			name: "an acquisition brings the rival along",
+			s:    State{Cash: b.AcquisitionCost, Users: 10, Market: 1000},
+			u:    UpgradeAcquisition,
+			want: State{
+				Acquisitions: 1,
+				Features:     b.AcquisitionFeatures,
+				Bugs:         b.AcquisitionBugs,
+				Users:        10 + b.AcquisitionUsers,
+				Market:       1000 + b.AcquisitionUsers,
+				Stats:        Stats{SpentOnUpgrades: b.AcquisitionCost},
+			},
+		},
+		{
+			name:    "the second perks cost more than the first",
+			s:       State{Cash: b.PerksCost, Perks: 1},
+			u:       UpgradePerks,
+			wantErr: ErrInsufficientCash,
+			want:    State{Cash: b.PerksCost, Perks: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Buy(b, tt.s, tt.u)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if s != tt.want {
+				t.Errorf("Buy =\n%+v\nwant\n%+v", s, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"theStartupTM/sim"
+)
+
+// runSim is the `sim` subcommand: it plays scripted strategies against the
+// economy with no terminal UI, writing the run as CSV to stdout and a summary
+// to stderr. It returns the process exit code.
+func runSim(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sim", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	balancePath := fs.String("balance", "", "read tuning numbers from this JSON file")
+	difficultyName := fs.String("difficulty", sim.Normal.String(), "difficulty preset")
+	strategyNames := fs.String("strategy", "mash,devs,qa", "comma separated strategies: idle, mash, devs, marketers, strategists, influencers, qa, raise, refactor, events")
+	devAbove := fs.Int("dev-above", 100, "devs: hire a dev whenever cash is over this")
+	marketerAbove := fs.Int("marketer-above", 1000, "marketers: hire a marketer whenever cash is over this")
+	strategistAbove := fs.Int("strategist-above", 5000, "strategists: hire a strategist whenever cash is over this")
+	influencerAbove := fs.Int("influencer-above", 20000, "influencers: hire an influencer whenever cash is over this")
+	refactorAbove := fs.Int("refactor-above", 50, "refactor: start a refactor sprint whenever tech debt is over this")
+	qaRatio := fs.Int("qa-ratio", 3, "qa: keep one QA for every this many devs")
+	ticks := fs.Int("ticks", 3600, "give up after this many one-second ticks")
+	every := fs.Int("every", 1, "write a CSV row every this many ticks")
+	seed := fs.Int64("seed", 1, "random seed for the run")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	balance := sim.DefaultBalance()
+	if *balancePath != "" {
+		b, err := sim.LoadBalance(*balancePath)
+		if err != nil {
+			fmt.Fprintf(stderr, "Couldn't read the balance sheet: %v\n", err)
+			return 1
+		}
+		balance = b
+	}
+	difficulty, err := sim.ParseDifficulty(*difficultyName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	balance = difficulty.Apply(balance)
+
+	strategies := map[string]sim.Strategy{
+		"idle":        sim.Idle,
+		"mash":        sim.Mash,
+		"devs":        sim.HireDevsAbove(*devAbove),
+		"marketers":   sim.HireMarketersAbove(*marketerAbove),
+		"strategists": sim.HireStrategistsAbove(*strategistAbove),
+		"influencers": sim.HireInfluencersAbove(*influencerAbove),
+		"qa":          sim.KeepQARatio(*qaRatio),
+		"raise":       sim.RaiseWhenOffered,
+		"refactor":    sim.RefactorAbove(*refactorAbove),
+		"events":      sim.TakeFirstChoice,
+	}
+	var chosen []sim.Strategy
+	for _, name := range strings.Split(*strategyNames, ",") {
+		strategy, ok := strategies[strings.TrimSpace(name)]
+		if !ok {
+			fmt.Fprintf(stderr, "unknown strategy %q\n", name)
+			return 2
+		}
+		chosen = append(chosen, strategy)
+	}
+
+	w := csv.NewWriter(stdout)
+	w.Write([]string{"tick", "cash", "cash_per_second", "price_per_share", "users", "features", "bugs", "devs", "qa", "marketers", "strategists", "influencers", "market", "fundamental_value", "ownership", "tech_debt"})
+	observe := func(tick int, s sim.State) {
+		if *every > 0 && tick%*every != 0 && !s.Over() {
+			return
+		}
+		w.Write([]string{
+			strconv.Itoa(tick),
+			strconv.Itoa(s.Cash),
+			strconv.Itoa(s.CashPerSecond),
+			strconv.Itoa(s.PricePerShare),
+			strconv.Itoa(s.Users),
+			strconv.Itoa(s.Features),
+			strconv.Itoa(s.Bugs),
+			strconv.Itoa(s.Devs),
+			strconv.Itoa(s.QA),
+			strconv.Itoa(s.Marketers),
+			strconv.Itoa(s.Strategists),
+			strconv.Itoa(s.Influencers),
+			strconv.Itoa(s.Market),
+			strconv.Itoa(s.FundamentalValue),
+			strconv.FormatFloat(s.Ownership, 'f', 3, 64),
+			strconv.Itoa(s.CopyPasteModifier),
+		})
+	}
+	_, result := sim.Run(balance, sim.New(balance, *seed), sim.Combine(chosen...), 1, *ticks, observe)
+	w.Flush()
+	if err := w.Error(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	cause := result.FailureCause
+	if cause == "" {
+		cause = "survived"
+	}
+	fmt.Fprintf(stderr, "seed:                 %d\n", *seed)
+	fmt.Fprintf(stderr, "survived:             %ds\n", result.Ticks)
+	fmt.Fprintf(stderr, "end:                  %s\n", strings.ReplaceAll(cause, "\n", " "))
+	fmt.Fprintf(stderr, "peak price per share: %d\n", result.PeakPricePerShare)
+	fmt.Fprintf(stderr, "peak cash:            %d\n", result.PeakCash)
+	return 0
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"theStartupTM/sim"
+)
+
+type startKeyMap struct {
+	Easier key.Binding
+	Harder key.Binding
+	Start  key.Binding
+	Load   key.Binding
+	Quit   key.Binding
+}
+
+func (k startKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Easier, k.Harder, k.Start, k.Load, k.Quit}
+}
+
+func (k startKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var startKeys = startKeyMap{
+	Easier: key.NewBinding(
+		key.WithKeys("left", "up"),
+		key.WithHelp("←", "easier"),
+	),
+	Harder: key.NewBinding(
+		key.WithKeys("right", "down"),
+		key.WithHelp("→", "harder"),
+	),
+	Start: key.NewBinding(
+		key.WithKeys("enter", " "),
+		key.WithHelp("enter", "start"),
+	),
+	Load: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "load saved game"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "quit"),
+	),
+}
+
+// startGame begins a fresh run at the chosen difficulty.
+func (m model) startGame() model {
+	m.balance = m.difficulty.Apply(m.baseBalance)
+	m.State = sim.New(m.balance, m.Seed)
+	m.scene = Game
+	return m
+}
+
+func (m model) updateStart(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, startKeys.Easier):
+		if m.difficulty > sim.Easy {
+			m.difficulty--
+		}
+
+	case key.Matches(msg, startKeys.Harder):
+		if m.difficulty < sim.Nightmare {
+			m.difficulty++
+		}
+
+	case key.Matches(msg, startKeys.Start):
+		return m.startGame(), nil
+
+	case m.hasSave && key.Matches(msg, startKeys.Load):
+		s, err := loadGame()
+		if err != nil {
+			m.notice = "Can't load: " + err.Error()
+			m.noticeFrames = NOTICE_FRAMES
+			return m, nil
+		}
+		return m.withSave(s).startRecording(), nil
+
+	case key.Matches(msg, startKeys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+var difficultySelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("35")).Bold(true)
+var difficultyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+// StartMenuView is the difficulty picker under the title.
+func (m model) StartMenuView() string {
+	choices := make([]string, len(sim.Difficulties))
+	for i, d := range sim.Difficulties {
+		if d == m.difficulty {
+			choices[i] = difficultySelectedStyle.Render("< " + d.String() + " >")
+		} else {
+			choices[i] = difficultyStyle.Render("  " + d.String() + "  ")
+		}
+	}
+
+	bindings := []key.Binding{startKeys.Easier, startKeys.Harder, startKeys.Start}
+	if m.hasSave {
+		bindings = append(bindings, startKeys.Load)
+	}
+	bindings = append(bindings, startKeys.Quit)
+
+	return "\n" + strings.Join(choices, "  ") + "\n\n" +
+		m.helpModel.ShortHelpView(bindings) + "\n" +
+		noticeStyle.Render(m.notice) + "\n" +
+		ScoresView(m.scores, 5, -1)
+}